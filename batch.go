@@ -0,0 +1,57 @@
+package store
+
+import "github.com/frozzare/go-store/driver"
+
+// GetMulti returns the values for the keys that exists in the store.
+// The values is decoded into the map args[0] points to if given. One
+// call is made if the driver implements driver.BatchDriver, otherwise
+// the keys is fetched one by one.
+func GetMulti(d driver.Driver, keys []string, args ...interface{}) (map[string]interface{}, error) {
+	if b, ok := d.(driver.BatchDriver); ok {
+		return b.GetMulti(keys, args...)
+	}
+
+	if err := driver.CheckArgs(args); err != nil {
+		return nil, err
+	}
+
+	return driver.DecodeMulti(keys, args, func(key string, v interface{}) error {
+		_, err := d.Get(key, v)
+
+		return err
+	})
+}
+
+// SetMulti sets the keys with values in the store. One call is made if
+// the driver implements driver.BatchDriver, otherwise the keys is set
+// one by one.
+func SetMulti(d driver.Driver, values map[string]interface{}) error {
+	if b, ok := d.(driver.BatchDriver); ok {
+		return b.SetMulti(values)
+	}
+
+	for key, value := range values {
+		if err := d.Set(key, value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DeleteMulti deletes the keys from the store. One call is made if
+// the driver implements driver.BatchDriver, otherwise the keys is
+// deleted one by one.
+func DeleteMulti(d driver.Driver, keys ...string) error {
+	if b, ok := d.(driver.BatchDriver); ok {
+		return b.DeleteMulti(keys...)
+	}
+
+	for _, key := range keys {
+		if err := d.Delete(key); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,37 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/frozzare/go-assert"
+	"github.com/frozzare/go-store/driver"
+	"github.com/frozzare/go-store/drivers/rwmutex"
+)
+
+// plain hides the optional interfaces of a driver.
+type plain struct {
+	driver.Driver
+}
+
+func TestMulti(t *testing.T) {
+	d, _ := rwmutex.Open()
+
+	for _, s := range []driver.Driver{d, plain{d}} {
+		assert.Nil(t, SetMulti(s, map[string]interface{}{"a": 1, "b": 2}))
+
+		values, err := GetMulti(s, []string{"a", "b", "c"})
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(values))
+		assert.Equal(t, float64(1), values["a"].(float64))
+
+		var ints map[string]int
+		_, err = GetMulti(s, []string{"a", "b", "c"}, &ints)
+		assert.Nil(t, err)
+		assert.Equal(t, 2, ints["b"])
+
+		assert.Nil(t, DeleteMulti(s, "a", "b"))
+
+		values, _ = GetMulti(s, []string{"a", "b"})
+		assert.Equal(t, 0, len(values))
+	}
+}
@@ -0,0 +1,28 @@
+package store
+
+import (
+	"fmt"
+
+	"github.com/frozzare/go-store/driver"
+)
+
+// Capabilities is a bitmask of the optional features a driver
+// supports, see the Cap constants in the driver package.
+type Capabilities = driver.Capabilities
+
+// CapabilitiesOf returns the optional features the driver supports.
+func CapabilitiesOf(d driver.Driver) Capabilities {
+	return driver.CapabilitiesOf(d)
+}
+
+// Supports reports whether the driver supports all the optional
+// features in c, e.g. Supports(d, driver.CapTTL|driver.CapWatch).
+func Supports(d driver.Driver, c Capabilities) bool {
+	return driver.CapabilitiesOf(d).Has(c)
+}
+
+// unsupported returns the error for a optional
+// feature the driver lacks.
+func unsupported(feature string) error {
+	return fmt.Errorf("store: driver does not support %s: %w", feature, ErrNotSupported)
+}
@@ -0,0 +1,34 @@
+package store
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/frozzare/go-assert"
+	"github.com/frozzare/go-store/driver"
+	"github.com/frozzare/go-store/drivers/rwmutex"
+)
+
+func TestSupports(t *testing.T) {
+	d, _ := rwmutex.Open()
+
+	assert.True(t, Supports(d, driver.CapTTL|driver.CapWatch))
+	assert.False(t, Supports(d, driver.CapNamespace))
+	assert.False(t, Supports(plain{d}, driver.CapTTL))
+
+	n, _ := Namespace(d, "users")
+	assert.True(t, Supports(n, driver.CapTTL|driver.CapCAS))
+	assert.False(t, Supports(n, driver.CapContext))
+
+	n, _ = Namespace(plain{d}, "users")
+	assert.Equal(t, Capabilities(0), CapabilitiesOf(n))
+
+	_, err := n.(driver.TTLDriver).TTL("name")
+	assert.True(t, errors.Is(err, ErrNotSupported))
+
+	_, err = Incr(plain{d}, "counter")
+	assert.True(t, errors.Is(err, ErrNotSupported))
+
+	_, err = SetIfAbsent(plain{d}, "name", "Fredrik")
+	assert.True(t, errors.Is(err, ErrNotSupported))
+}
@@ -0,0 +1,53 @@
+package store
+
+import (
+	"errors"
+
+	"github.com/frozzare/go-store/driver"
+)
+
+// UpdateRetries is how many times Update tries to
+// set a key before ErrConflict is returned.
+var UpdateRetries = 10
+
+// retry calls fn until it returns something else than ErrConflict
+// or UpdateRetries is reached. The driver must be a driver.CASDriver.
+func retry(d driver.Driver, fn func(c driver.CASDriver) error) error {
+	c, ok := d.(driver.CASDriver)
+
+	if !ok {
+		return unsupported("compare-and-set")
+	}
+
+	var err error
+
+	for i := 0; i < UpdateRetries; i++ {
+		if err = fn(c); !errors.Is(err, ErrConflict) {
+			return err
+		}
+	}
+
+	return err
+}
+
+// Update sets key to the value returned by fn for the current value,
+// old is nil if the key does not exist. The key is set with
+// compare-and-set and fn is called again if the key was changed by
+// someone else. Any error returned by fn is returned as is.
+func Update(d driver.Driver, key string, fn func(old interface{}) (interface{}, error)) error {
+	return retry(d, func(c driver.CASDriver) error {
+		old, version, err := c.GetWithVersion(key)
+
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			return err
+		}
+
+		value, err := fn(old)
+
+		if err != nil {
+			return err
+		}
+
+		return c.CompareAndSet(key, version, value)
+	})
+}
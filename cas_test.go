@@ -0,0 +1,54 @@
+package store
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/frozzare/go-assert"
+	"github.com/frozzare/go-store/drivers/rwmutex"
+)
+
+func TestUpdate(t *testing.T) {
+	d, _ := rwmutex.Open()
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			Update(d, "counter", func(old interface{}) (interface{}, error) {
+				if old == nil {
+					return float64(1), nil
+				}
+
+				return old.(float64) + 1, nil
+			})
+		}()
+	}
+
+	wg.Wait()
+
+	v, _ := d.Get("counter")
+	assert.Equal(t, float64(10), v.(float64))
+
+	assert.NotNil(t, Update(plain{d}, "counter", nil))
+}
+
+func TestTypedUpdate(t *testing.T) {
+	d, _ := rwmutex.Open()
+	s := NewTyped[int](d)
+
+	for i := 0; i < 3; i++ {
+		err := s.Update("counter", func(old int, ok bool) (int, error) {
+			assert.Equal(t, i > 0, ok)
+			return old + 1, nil
+		})
+		assert.Nil(t, err)
+	}
+
+	v, _, _ := s.Get("counter")
+	assert.Equal(t, 3, v)
+}
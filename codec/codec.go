@@ -0,0 +1,17 @@
+// Package codec contains the value codecs that can be used by the
+// store drivers to encode and decode values.
+//
+// A codec is passed to a driver as one of the open args:
+//
+//	s, err := boltdb.Open("/tmp/store.db", codec.Gob{})
+package codec
+
+// Codec is the interface that must be implemented
+// by a value codec used by a store driver.
+type Codec interface {
+	// Marshal encodes a value.
+	Marshal(v interface{}) ([]byte, error)
+
+	// Unmarshal decodes data into the value pointed to by v.
+	Unmarshal(data []byte, v interface{}) error
+}
@@ -0,0 +1,155 @@
+package codec_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/frozzare/go-assert"
+	"github.com/frozzare/go-store/codec"
+	"github.com/frozzare/go-store/driver"
+)
+
+type Person struct {
+	Name string
+}
+
+var codecs = map[string]driver.Codec{
+	"json":    codec.JSON{},
+	"gob":     codec.Gob{},
+	"msgpack": codec.Msgpack{},
+}
+
+func TestStruct(t *testing.T) {
+	for name, c := range codecs {
+		b, err := c.Marshal(&Person{Name: "Fredrik"})
+		assert.Nil(t, err, name)
+
+		var p *Person
+		assert.Nil(t, c.Unmarshal(b, &p), name)
+		assert.Equal(t, "Fredrik", p.Name, name)
+	}
+}
+
+func TestStringLooksLikeNumber(t *testing.T) {
+	for name, c := range codecs {
+		b, err := c.Marshal("123")
+		assert.Nil(t, err, name)
+
+		var s string
+		assert.Nil(t, c.Unmarshal(b, &s), name)
+		assert.Equal(t, "123", s, name)
+	}
+}
+
+func TestMsgpackMap(t *testing.T) {
+	c := codec.Msgpack{}
+
+	b, _ := c.Marshal(map[string]interface{}{"hello": "world"})
+
+	var v interface{}
+	assert.Nil(t, c.Unmarshal(b, &v))
+	assert.Equal(t, "world", v.(map[string]interface{})["hello"].(string))
+}
+
+func TestRaw(t *testing.T) {
+	c := codec.Raw{}
+
+	b, err := c.Marshal("Fredrik")
+	assert.Nil(t, err)
+	assert.Equal(t, "Fredrik", string(b))
+
+	var s string
+	assert.Nil(t, c.Unmarshal(b, &s))
+	assert.Equal(t, "Fredrik", s)
+
+	var v interface{}
+	assert.Nil(t, c.Unmarshal(b, &v))
+	assert.Equal(t, []byte("Fredrik"), v.([]byte))
+
+	_, err = c.Marshal(123)
+	assert.NotNil(t, err)
+}
+
+func TestEnvelope(t *testing.T) {
+	c := codec.Envelope{}
+	now := time.Now().UTC()
+
+	values := []interface{}{
+		nil,
+		"123",
+		true,
+		42,
+		int64(1<<62 + 1),
+		uint8(7),
+		float32(1.5),
+		3.14,
+		[]byte("Fredrik"),
+		json.RawMessage(`{"hello":"world"}`),
+		now,
+	}
+
+	for _, value := range values {
+		b, err := c.Marshal(value)
+		assert.Nil(t, err)
+
+		var v interface{}
+		assert.Nil(t, c.Unmarshal(b, &v))
+		assert.Equal(t, reflect.TypeOf(value), reflect.TypeOf(v))
+		assert.True(t, reflect.DeepEqual(value, v), fmt.Sprintf("%#v != %#v", value, v))
+	}
+}
+
+func TestEnvelopeInto(t *testing.T) {
+	c := codec.Envelope{}
+
+	b, _ := c.Marshal(42)
+
+	var i int64
+	assert.Nil(t, c.Unmarshal(b, &i))
+	assert.Equal(t, int64(42), i)
+
+	var s string
+	assert.NotNil(t, c.Unmarshal(b, &s))
+
+	var f float64
+	assert.NotNil(t, c.Unmarshal(b, &f))
+
+	var i8 int8
+	assert.Nil(t, c.Unmarshal(b, &i8))
+	assert.Equal(t, int8(42), i8)
+
+	var u uint
+	assert.Nil(t, c.Unmarshal(b, &u))
+	assert.Equal(t, uint(42), u)
+
+	b, _ = c.Marshal(int64(300))
+	assert.NotNil(t, c.Unmarshal(b, &i8))
+
+	b, _ = c.Marshal(-1)
+	assert.NotNil(t, c.Unmarshal(b, &u))
+
+	b, _ = c.Marshal(uint(7))
+	assert.Nil(t, c.Unmarshal(b, &i))
+	assert.Equal(t, int64(7), i)
+
+	b, _ = c.Marshal(1.9)
+
+	var n int
+	assert.NotNil(t, c.Unmarshal(b, &n))
+
+	var f32 float32
+	assert.Nil(t, c.Unmarshal(b, &f32))
+	assert.Equal(t, float32(1.9), f32)
+
+	b, _ = c.Marshal(1e300)
+	assert.NotNil(t, c.Unmarshal(b, &f32))
+
+	b, _ = c.Marshal(&Person{Name: "Fredrik"})
+
+	var p *Person
+	assert.Nil(t, c.Unmarshal(b, &p))
+	assert.Equal(t, "Fredrik", p.Name)
+}
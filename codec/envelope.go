@@ -0,0 +1,263 @@
+package codec
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// Envelope wraps the encoded value together with its original type,
+// so integers, nil, []byte, time.Time and json.RawMessage values is
+// returned with the same type they were stored with.
+//
+// Values of other types are encoded with Codec, which defaults to JSON.
+//
+// The stored format is the type name, a colon and the value,
+// e.g. "int64:42".
+type Envelope struct {
+	Codec Codec
+}
+
+// envelope type names for values that is not encoded with the codec.
+const (
+	typeNil   = "nil"
+	typeBytes = "bytes"
+	typeJSON  = "json"
+	typeTime  = "time"
+	typeValue = "value"
+)
+
+// codec returns the codec used for other values.
+func (e Envelope) codec() Codec {
+	if e.Codec == nil {
+		return JSON{}
+	}
+
+	return e.Codec
+}
+
+// Marshal returns the envelope encoding of v.
+func (e Envelope) Marshal(v interface{}) ([]byte, error) {
+	var name string
+	var data []byte
+
+	switch v := v.(type) {
+	case nil:
+		name = typeNil
+	case []byte:
+		name, data = typeBytes, v
+	case json.RawMessage:
+		name, data = typeJSON, v
+	case string:
+		name, data = "string", []byte(v)
+	case bool:
+		name, data = "bool", strconv.AppendBool(nil, v)
+	case int, int8, int16, int32, int64:
+		name = reflect.TypeOf(v).Name()
+		data = strconv.AppendInt(nil, reflect.ValueOf(v).Int(), 10)
+	case uint, uint8, uint16, uint32, uint64:
+		name = reflect.TypeOf(v).Name()
+		data = strconv.AppendUint(nil, reflect.ValueOf(v).Uint(), 10)
+	case float32:
+		name, data = "float32", strconv.AppendFloat(nil, float64(v), 'g', -1, 32)
+	case float64:
+		name, data = "float64", strconv.AppendFloat(nil, v, 'g', -1, 64)
+	case time.Time:
+		b, err := v.MarshalText()
+
+		if err != nil {
+			return nil, err
+		}
+
+		name, data = typeTime, b
+	default:
+		b, err := e.codec().Marshal(v)
+
+		if err != nil {
+			return nil, err
+		}
+
+		name, data = typeValue, b
+	}
+
+	return append([]byte(name+":"), data...), nil
+}
+
+// Unmarshal parses the envelope encoded data into the value pointed to by v.
+func (e Envelope) Unmarshal(data []byte, v interface{}) error {
+	i := bytes.IndexByte(data, ':')
+
+	if i == -1 {
+		return fmt.Errorf("codec: invalid envelope")
+	}
+
+	name, data := string(data[:i]), data[i+1:]
+
+	if name == typeValue {
+		return e.codec().Unmarshal(data, v)
+	}
+
+	value, err := decodeEnvelope(name, data)
+
+	if err != nil {
+		return err
+	}
+
+	rv := reflect.ValueOf(v)
+
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("codec: envelope can not unmarshal into %T", v)
+	}
+
+	rv = rv.Elem()
+
+	if value == nil {
+		rv.Set(reflect.Zero(rv.Type()))
+		return nil
+	}
+
+	xv := reflect.ValueOf(value)
+
+	if xv.Type().AssignableTo(rv.Type()) {
+		rv.Set(xv)
+		return nil
+	}
+
+	cv, ok := convert(xv, rv.Type())
+
+	if !ok {
+		return fmt.Errorf("codec: envelope can not unmarshal %s %v into %s", name, value, rv.Type())
+	}
+
+	rv.Set(cv)
+
+	return nil
+}
+
+// convert converts xv to the type t if it can be done without losing
+// anything. Integers is converted to integers and floats to floats if
+// the value fits in t, other values only to types of the same kind.
+func convert(xv reflect.Value, t reflect.Type) (reflect.Value, bool) {
+	cv := reflect.New(t).Elem()
+
+	switch {
+	case isInt(xv.Kind()) && isInt(t.Kind()):
+		if cv.OverflowInt(xv.Int()) {
+			return cv, false
+		}
+
+		cv.SetInt(xv.Int())
+	case isUint(xv.Kind()) && isUint(t.Kind()):
+		if cv.OverflowUint(xv.Uint()) {
+			return cv, false
+		}
+
+		cv.SetUint(xv.Uint())
+	case isInt(xv.Kind()) && isUint(t.Kind()):
+		if xv.Int() < 0 || cv.OverflowUint(uint64(xv.Int())) {
+			return cv, false
+		}
+
+		cv.SetUint(uint64(xv.Int()))
+	case isUint(xv.Kind()) && isInt(t.Kind()):
+		if xv.Uint() > math.MaxInt64 || cv.OverflowInt(int64(xv.Uint())) {
+			return cv, false
+		}
+
+		cv.SetInt(int64(xv.Uint()))
+	case isFloat(xv.Kind()) && isFloat(t.Kind()):
+		if cv.OverflowFloat(xv.Float()) {
+			return cv, false
+		}
+
+		cv.SetFloat(xv.Float())
+	case xv.Kind() == t.Kind() && xv.Type().ConvertibleTo(t):
+		return xv.Convert(t), true
+	default:
+		return cv, false
+	}
+
+	return cv, true
+}
+
+// isInt reports if the kind is a signed integer.
+func isInt(k reflect.Kind) bool {
+	return k >= reflect.Int && k <= reflect.Int64
+}
+
+// isUint reports if the kind is a unsigned integer.
+func isUint(k reflect.Kind) bool {
+	return k >= reflect.Uint && k <= reflect.Uintptr
+}
+
+// isFloat reports if the kind is a float.
+func isFloat(k reflect.Kind) bool {
+	return k == reflect.Float32 || k == reflect.Float64
+}
+
+// decodeEnvelope decodes the envelope data for the given type name.
+func decodeEnvelope(name string, data []byte) (interface{}, error) {
+	switch name {
+	case typeNil:
+		return nil, nil
+	case typeBytes:
+		return append([]byte{}, data...), nil
+	case typeJSON:
+		return json.RawMessage(append([]byte{}, data...)), nil
+	case "string":
+		return string(data), nil
+	case "bool":
+		return strconv.ParseBool(string(data))
+	case typeTime:
+		var t time.Time
+		err := t.UnmarshalText(data)
+		return t, err
+	case "float32":
+		f, err := strconv.ParseFloat(string(data), 32)
+		return float32(f), err
+	case "float64":
+		return strconv.ParseFloat(string(data), 64)
+	}
+
+	if typ, ok := intTypes[name]; ok {
+		i, err := strconv.ParseInt(string(data), 10, typ.Bits())
+
+		if err != nil {
+			return nil, err
+		}
+
+		return reflect.ValueOf(i).Convert(typ).Interface(), nil
+	}
+
+	if typ, ok := uintTypes[name]; ok {
+		i, err := strconv.ParseUint(string(data), 10, typ.Bits())
+
+		if err != nil {
+			return nil, err
+		}
+
+		return reflect.ValueOf(i).Convert(typ).Interface(), nil
+	}
+
+	return nil, fmt.Errorf("codec: unknown envelope type %q", name)
+}
+
+var intTypes = map[string]reflect.Type{
+	"int":   reflect.TypeOf(int(0)),
+	"int8":  reflect.TypeOf(int8(0)),
+	"int16": reflect.TypeOf(int16(0)),
+	"int32": reflect.TypeOf(int32(0)),
+	"int64": reflect.TypeOf(int64(0)),
+}
+
+var uintTypes = map[string]reflect.Type{
+	"uint":   reflect.TypeOf(uint(0)),
+	"uint8":  reflect.TypeOf(uint8(0)),
+	"uint16": reflect.TypeOf(uint16(0)),
+	"uint32": reflect.TypeOf(uint32(0)),
+	"uint64": reflect.TypeOf(uint64(0)),
+}
@@ -0,0 +1,28 @@
+package codec
+
+import (
+	"bytes"
+	"encoding/gob"
+)
+
+// Gob encodes values with encoding/gob.
+//
+// Gob needs to know the type to decode into, so values
+// must be read back with a pointer to the original type.
+type Gob struct{}
+
+// Marshal returns the gob encoding of v.
+func (Gob) Marshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+
+	if err := gob.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Unmarshal parses the gob encoded data into the value pointed to by v.
+func (Gob) Unmarshal(data []byte, v interface{}) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(v)
+}
@@ -0,0 +1,16 @@
+package codec
+
+import "encoding/json"
+
+// JSON encodes values as JSON. It's the default codec.
+type JSON struct{}
+
+// Marshal returns the JSON encoding of v.
+func (JSON) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// Unmarshal parses the JSON encoded data into the value pointed to by v.
+func (JSON) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
@@ -0,0 +1,59 @@
+package codec
+
+import (
+	"bytes"
+
+	"gopkg.in/vmihailenco/msgpack.v2"
+)
+
+// Msgpack encodes values as MessagePack.
+//
+// Maps decoded into a interface{} is returned as map[string]interface{}
+// to match the JSON codec.
+type Msgpack struct{}
+
+// Marshal returns the MessagePack encoding of v.
+func (Msgpack) Marshal(v interface{}) ([]byte, error) {
+	return msgpack.Marshal(v)
+}
+
+// Unmarshal parses the MessagePack encoded data into the value pointed to by v.
+func (Msgpack) Unmarshal(data []byte, v interface{}) error {
+	dec := msgpack.NewDecoder(bytes.NewReader(data))
+	dec.DecodeMapFunc = decodeMap
+
+	return dec.Decode(v)
+}
+
+// decodeMap decodes a MessagePack map into a map[string]interface{}.
+func decodeMap(d *msgpack.Decoder) (interface{}, error) {
+	n, err := d.DecodeMapLen()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if n == -1 {
+		return nil, nil
+	}
+
+	m := make(map[string]interface{}, n)
+
+	for i := 0; i < n; i++ {
+		key, err := d.DecodeString()
+
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := d.DecodeInterface()
+
+		if err != nil {
+			return nil, err
+		}
+
+		m[key] = value
+	}
+
+	return m, nil
+}
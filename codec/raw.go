@@ -0,0 +1,44 @@
+package codec
+
+import (
+	"encoding"
+	"fmt"
+)
+
+// Raw stores values as is without any encoding. It accepts
+// []byte, string and encoding.BinaryMarshaler values.
+//
+// Values read into a interface{} is returned as []byte.
+type Raw struct{}
+
+// Marshal returns the bytes of v.
+func (Raw) Marshal(v interface{}) ([]byte, error) {
+	switch v := v.(type) {
+	case []byte:
+		return v, nil
+	case string:
+		return []byte(v), nil
+	case encoding.BinaryMarshaler:
+		return v.MarshalBinary()
+	}
+
+	return nil, fmt.Errorf("codec: raw can not marshal %T", v)
+}
+
+// Unmarshal copies data into the value pointed to by v.
+func (Raw) Unmarshal(data []byte, v interface{}) error {
+	switch v := v.(type) {
+	case *[]byte:
+		*v = append([]byte(nil), data...)
+	case *string:
+		*v = string(data)
+	case *interface{}:
+		*v = append([]byte(nil), data...)
+	case encoding.BinaryUnmarshaler:
+		return v.UnmarshalBinary(data)
+	default:
+		return fmt.Errorf("codec: raw can not unmarshal into %T", v)
+	}
+
+	return nil
+}
@@ -0,0 +1,48 @@
+package store
+
+import "github.com/frozzare/go-store/driver"
+
+// counter returns the driver as a driver.CounterDriver.
+func counter(d driver.Driver) (driver.CounterDriver, error) {
+	c, ok := d.(driver.CounterDriver)
+
+	if !ok {
+		return nil, unsupported("counters")
+	}
+
+	return c, nil
+}
+
+// IncrBy atomically adds delta to the integer value of key and
+// returns the new value. A key that does not exist is set to delta.
+func IncrBy(d driver.Driver, key string, delta int64) (int64, error) {
+	c, err := counter(d)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return c.IncrBy(key, delta)
+}
+
+// IncrByFloat atomically adds delta to the float value of key and
+// returns the new value. A key that does not exist is set to delta.
+func IncrByFloat(d driver.Driver, key string, delta float64) (float64, error) {
+	c, err := counter(d)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return c.IncrByFloat(key, delta)
+}
+
+// Incr atomically increments the integer value of key by one.
+func Incr(d driver.Driver, key string) (int64, error) {
+	return IncrBy(d, key, 1)
+}
+
+// Decr atomically decrements the integer value of key by one.
+func Decr(d driver.Driver, key string) (int64, error) {
+	return IncrBy(d, key, -1)
+}
@@ -0,0 +1,36 @@
+package store
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/frozzare/go-assert"
+	"github.com/frozzare/go-store/drivers/rwmutex"
+)
+
+func TestIncrDecr(t *testing.T) {
+	d, _ := rwmutex.Open()
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			Incr(d, "counter")
+		}()
+	}
+
+	wg.Wait()
+
+	n, err := Decr(d, "counter")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(9), n)
+
+	v, _ := d.Get("counter")
+	assert.Equal(t, float64(9), v.(float64))
+
+	_, err = Incr(plain{d}, "counter")
+	assert.NotNil(t, err)
+}
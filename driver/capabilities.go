@@ -0,0 +1,162 @@
+package driver
+
+import "strings"
+
+// Capabilities is a bitmask of the optional features a driver supports.
+type Capabilities uint32
+
+// The optional features, one for each of the optional interfaces.
+const (
+	// CapContext is set for drivers that implements ContextDriver.
+	CapContext Capabilities = 1 << iota
+
+	// CapTTL is set for drivers that implements TTLDriver.
+	CapTTL
+
+	// CapBatch is set for drivers that implements BatchDriver.
+	CapBatch
+
+	// CapTx is set for drivers that implements TxDriver.
+	CapTx
+
+	// CapScan is set for drivers that implements ScanDriver.
+	CapScan
+
+	// CapMatch is set for drivers that implements MatchDriver.
+	CapMatch
+
+	// CapRange is set for drivers that implements RangeDriver.
+	CapRange
+
+	// CapCAS is set for drivers that implements CASDriver.
+	CapCAS
+
+	// CapCounter is set for drivers that implements CounterDriver.
+	CapCounter
+
+	// CapConditional is set for drivers that implements ConditionalDriver.
+	CapConditional
+
+	// CapWatch is set for drivers that implements WatchDriver.
+	CapWatch
+
+	// CapNamespace is set for drivers that implements NamespaceDriver.
+	CapNamespace
+
+	// CapPrefix is set for drivers that implements PrefixDriver.
+	CapPrefix
+)
+
+// capabilityNames is the names of the capabilities in bit order.
+var capabilityNames = []string{
+	"context",
+	"ttl",
+	"batch",
+	"tx",
+	"scan",
+	"match",
+	"range",
+	"cas",
+	"counter",
+	"conditional",
+	"watch",
+	"namespace",
+	"prefix",
+}
+
+// Has reports whether all capabilities in o is set.
+func (c Capabilities) Has(o Capabilities) bool {
+	return c&o == o
+}
+
+// String returns the names of the capabilities separated by |.
+func (c Capabilities) String() string {
+	var names []string
+
+	for i, name := range capabilityNames {
+		if c&(1<<uint(i)) != 0 {
+			names = append(names, name)
+		}
+	}
+
+	if len(names) == 0 {
+		return "none"
+	}
+
+	return strings.Join(names, "|")
+}
+
+// CapabilitiesDriver is the interface that can be implemented by a
+// store driver to report the optional features it supports, e.g. when
+// it wraps another driver and only supports what that driver does.
+type CapabilitiesDriver interface {
+	Driver
+
+	// Capabilities returns the optional features the driver supports.
+	Capabilities() Capabilities
+}
+
+// CapabilitiesOf returns the optional features the driver supports. The
+// Capabilities method is used if the driver implements
+// CapabilitiesDriver, otherwise the optional interfaces is checked.
+func CapabilitiesOf(d Driver) Capabilities {
+	if c, ok := d.(CapabilitiesDriver); ok {
+		return c.Capabilities()
+	}
+
+	var c Capabilities
+
+	if _, ok := d.(ContextDriver); ok {
+		c |= CapContext
+	}
+
+	if _, ok := d.(TTLDriver); ok {
+		c |= CapTTL
+	}
+
+	if _, ok := d.(BatchDriver); ok {
+		c |= CapBatch
+	}
+
+	if _, ok := d.(TxDriver); ok {
+		c |= CapTx
+	}
+
+	if _, ok := d.(ScanDriver); ok {
+		c |= CapScan
+	}
+
+	if _, ok := d.(MatchDriver); ok {
+		c |= CapMatch
+	}
+
+	if _, ok := d.(RangeDriver); ok {
+		c |= CapRange
+	}
+
+	if _, ok := d.(CASDriver); ok {
+		c |= CapCAS
+	}
+
+	if _, ok := d.(CounterDriver); ok {
+		c |= CapCounter
+	}
+
+	if _, ok := d.(ConditionalDriver); ok {
+		c |= CapConditional
+	}
+
+	if _, ok := d.(WatchDriver); ok {
+		c |= CapWatch
+	}
+
+	if _, ok := d.(NamespaceDriver); ok {
+		c |= CapNamespace
+	}
+
+	if _, ok := d.(PrefixDriver); ok {
+		c |= CapPrefix
+	}
+
+	return c
+}
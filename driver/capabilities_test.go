@@ -0,0 +1,28 @@
+package driver_test
+
+import (
+	"testing"
+
+	"github.com/frozzare/go-assert"
+	"github.com/frozzare/go-store/driver"
+	"github.com/frozzare/go-store/drivers/rwmutex"
+)
+
+func TestCapabilities(t *testing.T) {
+	c := driver.CapTTL | driver.CapWatch
+
+	assert.True(t, c.Has(driver.CapTTL))
+	assert.False(t, c.Has(driver.CapTTL|driver.CapTx))
+	assert.Equal(t, "ttl|watch", c.String())
+	assert.Equal(t, "none", driver.Capabilities(0).String())
+}
+
+func TestCapabilitiesOf(t *testing.T) {
+	d, _ := rwmutex.Open()
+
+	assert.True(t, driver.CapabilitiesOf(d).Has(driver.CapTTL|driver.CapCAS|driver.CapWatch))
+	assert.False(t, driver.CapabilitiesOf(d).Has(driver.CapNamespace))
+
+	// Only the methods of driver.Driver is promoted.
+	assert.Equal(t, driver.Capabilities(0), driver.CapabilitiesOf(struct{ driver.Driver }{d}))
+}
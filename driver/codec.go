@@ -0,0 +1,155 @@
+package driver
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+
+	"github.com/frozzare/go-store/codec"
+)
+
+// Codec is the interface that must be implemented
+// by a value codec used by a store driver.
+type Codec = codec.Codec
+
+// CodecArgs returns the first Codec found in the open args and
+// the args without it. The JSON codec is returned if none is found.
+func CodecArgs(args []interface{}) (Codec, []interface{}) {
+	var c Codec
+	var rest []interface{}
+
+	for _, arg := range args {
+		if v, ok := arg.(Codec); ok && c == nil {
+			c = v
+			continue
+		}
+
+		rest = append(rest, arg)
+	}
+
+	if c == nil {
+		c = codec.JSON{}
+	}
+
+	return c, rest
+}
+
+// Decode decodes data with the codec. The value is decoded into
+// args[0] if given, otherwise the decoded value is returned.
+func Decode(c Codec, data []byte, args []interface{}) (interface{}, error) {
+	if len(args) > 0 {
+		return nil, Unmarshal(c, data, args[0])
+	}
+
+	var value interface{}
+
+	if err := Unmarshal(c, data, &value); err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}
+
+// Unmarshal decodes data with the codec into v. Stores written
+// before codecs was added holds strings as is, so data that is not
+// valid JSON is decoded as a string when the codec is JSON and v
+// points to a string, []byte or interface{}.
+func Unmarshal(c Codec, data []byte, v interface{}) error {
+	err := c.Unmarshal(data, v)
+
+	if _, ok := c.(codec.JSON); !ok || err == nil {
+		return err
+	}
+
+	var syntax *json.SyntaxError
+
+	if !errors.As(err, &syntax) {
+		return err
+	}
+
+	switch v := v.(type) {
+	case *string:
+		*v = string(data)
+	case *[]byte:
+		*v = append([]byte(nil), data...)
+	case *interface{}:
+		*v = string(data)
+	default:
+		return err
+	}
+
+	return nil
+}
+
+// DecodeMulti decodes the values for the keys with the decode func,
+// keys that decode returns ErrNotFound for is left out. The values is
+// decoded into the map args[0] points to if given, otherwise a map
+// with the decoded values is returned.
+func DecodeMulti(keys []string, args []interface{}, decode func(key string, v interface{}) error) (map[string]interface{}, error) {
+	if len(args) == 0 {
+		values := make(map[string]interface{}, len(keys))
+
+		for _, key := range keys {
+			var value interface{}
+
+			if err := decode(key, &value); errors.Is(err, ErrNotFound) {
+				continue
+			} else if err != nil {
+				return nil, err
+			}
+
+			values[key] = value
+		}
+
+		return values, nil
+	}
+
+	m := reflect.ValueOf(args[0])
+
+	if m.Kind() != reflect.Ptr || m.IsNil() || m.Elem().Kind() != reflect.Map || m.Elem().Type().Key().Kind() != reflect.String {
+		return nil, ErrInvalidArgs
+	}
+
+	m = m.Elem()
+
+	if m.IsNil() {
+		m.Set(reflect.MakeMapWithSize(m.Type(), len(keys)))
+	}
+
+	for _, key := range keys {
+		value := reflect.New(m.Type().Elem())
+
+		if err := decode(key, value.Interface()); errors.Is(err, ErrNotFound) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		m.SetMapIndex(reflect.ValueOf(key).Convert(m.Type().Key()), value.Elem())
+	}
+
+	return nil, nil
+}
+
+// Add decodes data as a number with the codec, adds delta to it and
+// returns the sum and the sum encoded with the codec. Nil data is
+// decoded as zero.
+func Add[T int64 | float64](c Codec, data []byte, delta T) (T, []byte, error) {
+	var n T
+
+	if data != nil {
+		if err := c.Unmarshal(data, &n); err != nil {
+			return 0, nil, err
+		}
+	}
+
+	n += delta
+
+	data, err := c.Marshal(n)
+
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return n, data, nil
+}
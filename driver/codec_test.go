@@ -0,0 +1,33 @@
+package driver_test
+
+import (
+	"testing"
+
+	"github.com/frozzare/go-assert"
+	"github.com/frozzare/go-store/codec"
+	"github.com/frozzare/go-store/driver"
+)
+
+func TestDecodeLegacy(t *testing.T) {
+	// Strings was stored as is before codecs was added.
+	v, err := driver.Decode(codec.JSON{}, []byte("Fredrik"), nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "Fredrik", v)
+
+	var s string
+	_, err = driver.Decode(codec.JSON{}, []byte("Fredrik"), []interface{}{&s})
+	assert.Nil(t, err)
+	assert.Equal(t, "Fredrik", s)
+
+	var n int
+	_, err = driver.Decode(codec.JSON{}, []byte("Fredrik"), []interface{}{&n})
+	assert.NotNil(t, err)
+
+	// Only the JSON codec falls back to the raw string.
+	_, err = driver.Decode(codec.Gob{}, []byte("Fredrik"), []interface{}{&s})
+	assert.NotNil(t, err)
+
+	v, err = driver.Decode(codec.JSON{}, []byte(`{"name":"Fredrik"}`), nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "Fredrik", v.(map[string]interface{})["name"])
+}
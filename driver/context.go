@@ -0,0 +1,131 @@
+package driver
+
+import "context"
+
+// contextDriver wraps a driver that does not implement
+// ContextDriver and runs each call in its own goroutine
+// so the caller can return when the context is done.
+type contextDriver struct {
+	Driver
+}
+
+// WithContext returns a ContextDriver for the given driver. Drivers
+// that already implements ContextDriver is returned as is.
+//
+// Other drivers is wrapped so a call returns with the context error
+// as soon as the context is done. The underlying call can not be
+// interrupted and will finish in the background.
+func WithContext(d Driver) ContextDriver {
+	if cd, ok := d.(ContextDriver); ok {
+		return cd
+	}
+
+	return &contextDriver{Driver: d}
+}
+
+// Run runs fn in a new goroutine and waits for it to finish or the
+// context to be done. The context error is returned as soon as the
+// context is done, but fn is not interrupted and finishes in the
+// background, so drivers without context support can use it for
+// their Context methods.
+func Run(ctx context.Context, fn func() error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	done := make(chan error, 1)
+
+	go func() {
+		done <- fn()
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// CountContext counts the number of keys in the store.
+func (d *contextDriver) CountContext(ctx context.Context) (int64, error) {
+	var count int64
+
+	err := Run(ctx, func() (err error) {
+		count, err = d.Count()
+		return
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// DeleteContext delets a key and value from store if any.
+func (d *contextDriver) DeleteContext(ctx context.Context, key string) error {
+	return Run(ctx, func() error {
+		return d.Delete(key)
+	})
+}
+
+// ExistsContext checks if a key exists in the store.
+func (d *contextDriver) ExistsContext(ctx context.Context, key string) (bool, error) {
+	var exists bool
+
+	err := Run(ctx, func() (err error) {
+		exists, err = d.Exists(key)
+		return
+	})
+
+	if err != nil {
+		return false, err
+	}
+
+	return exists, nil
+}
+
+// GetContext returns the value for a key if any.
+func (d *contextDriver) GetContext(ctx context.Context, key string, args ...interface{}) (interface{}, error) {
+	var value interface{}
+
+	err := Run(ctx, func() (err error) {
+		value, err = d.Get(key, args...)
+		return
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}
+
+// KeysContext returns a string slice with all keys.
+func (d *contextDriver) KeysContext(ctx context.Context) ([]string, error) {
+	var keys []string
+
+	err := Run(ctx, func() (err error) {
+		keys, err = d.Keys()
+		return
+	})
+
+	if err != nil {
+		return []string{}, err
+	}
+
+	return keys, nil
+}
+
+// SetContext key value in store.
+func (d *contextDriver) SetContext(ctx context.Context, key string, value interface{}) error {
+	return Run(ctx, func() error {
+		return d.Set(key, value)
+	})
+}
+
+// FlushContext will remove all keys and values from the store.
+func (d *contextDriver) FlushContext(ctx context.Context) error {
+	return Run(ctx, d.Flush)
+}
@@ -0,0 +1,42 @@
+package driver_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/frozzare/go-assert"
+	"github.com/frozzare/go-store/driver"
+	"github.com/frozzare/go-store/drivers/rwmutex"
+)
+
+type slowDriver struct {
+	driver.Driver
+}
+
+func (s *slowDriver) Get(key string, args ...interface{}) (interface{}, error) {
+	time.Sleep(100 * time.Millisecond)
+	return s.Driver.Get(key, args...)
+}
+
+func TestWithContext(t *testing.T) {
+	d, _ := rwmutex.Open()
+	s := driver.WithContext(&slowDriver{d})
+
+	s.SetContext(context.Background(), "name", "Fredrik")
+
+	v, _ := s.GetContext(context.Background(), "name")
+	assert.Equal(t, "Fredrik", v.(string))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	v, err := s.GetContext(ctx, "name")
+	assert.Nil(t, v)
+	assert.Equal(t, context.DeadlineExceeded, err)
+}
+
+func TestWithContextDriver(t *testing.T) {
+	d, _ := rwmutex.Open()
+	assert.Equal(t, d, driver.WithContext(d))
+}
@@ -1,5 +1,10 @@
 package driver
 
+import (
+	"context"
+	"time"
+)
+
 // Driver is the interface that must be implemented
 // by a store driver.
 type Driver interface {
@@ -30,3 +35,216 @@ type Driver interface {
 	// Flush will remove all keys and values from the store.
 	Flush() error
 }
+
+// ContextDriver is the interface that can be implemented by a store
+// driver to support context deadlines and cancellation.
+type ContextDriver interface {
+	Driver
+
+	// CountContext counts the number of keys in the store.
+	CountContext(ctx context.Context) (int64, error)
+
+	// DeleteContext delets a key and value from store if any.
+	DeleteContext(ctx context.Context, key string) error
+
+	// ExistsContext checks if a key exists in the store.
+	ExistsContext(ctx context.Context, key string) (bool, error)
+
+	// GetContext returns the value for a key if any.
+	GetContext(ctx context.Context, key string, args ...interface{}) (interface{}, error)
+
+	// KeysContext returns a string slice with all keys.
+	KeysContext(ctx context.Context) ([]string, error)
+
+	// SetContext key value in store.
+	SetContext(ctx context.Context, key string, value interface{}) error
+
+	// FlushContext will remove all keys and values from the store.
+	FlushContext(ctx context.Context) error
+}
+
+// NoExpiration is returned by TTL for keys that does not expire.
+const NoExpiration time.Duration = -1
+
+// TTLDriver is the interface that can be implemented by a store
+// driver to support key expiration. Expired keys is not visible
+// to Get, Exists, Keys or Count.
+type TTLDriver interface {
+	Driver
+
+	// SetWithTTL key value in store that expires after the ttl.
+	SetWithTTL(key string, value interface{}, ttl time.Duration) error
+
+	// TTL returns the time left before the key expires
+	// or NoExpiration if the key does not expire.
+	TTL(key string) (time.Duration, error)
+
+	// Expire sets the ttl for a existing key.
+	Expire(key string, ttl time.Duration) error
+
+	// Persist removes the expiration from a existing key.
+	Persist(key string) error
+}
+
+// BatchDriver is the interface that can be implemented by a store
+// driver to get, set and delete many keys in one call.
+type BatchDriver interface {
+	Driver
+
+	// GetMulti returns the values for the keys that exists in store.
+	// The values is decoded into the map args[0] points to if given.
+	GetMulti(keys []string, args ...interface{}) (map[string]interface{}, error)
+
+	// SetMulti keys with values in store.
+	SetMulti(values map[string]interface{}) error
+
+	// DeleteMulti keys from store.
+	DeleteMulti(keys ...string) error
+}
+
+// Tx is the interface that is passed to the func given
+// to TxDriver.Update to read and write keys in a transaction.
+type Tx interface {
+	// Exists returns true when a key exists false when not existing in store.
+	Exists(key string) (bool, error)
+
+	// Get returns the value for a key if any.
+	Get(key string, args ...interface{}) (interface{}, error)
+
+	// Set key with value in store.
+	Set(key string, value interface{}) error
+
+	// Delete key from store.
+	Delete(key string) error
+}
+
+// TxDriver is the interface that can be implemented by a store
+// driver to read and write many keys in one transaction.
+type TxDriver interface {
+	Driver
+
+	// Update runs fn in a transaction. The changes is committed if
+	// fn returns nil and rolled back if fn returns a error.
+	Update(fn func(tx Tx) error) error
+}
+
+// ScanDriver is the interface that can be implemented by a store
+// driver to iterate over the keys a page at the time.
+type ScanDriver interface {
+	Driver
+
+	// Scan returns up to limit keys with the prefix starting at the
+	// cursor, an empty cursor starts from the beginning. The returned
+	// cursor is passed to the next call and is empty when there is no
+	// more keys. A limit less than one returns all remaining keys.
+	Scan(prefix, cursor string, limit int) ([]string, string, error)
+}
+
+// MatchDriver is the interface that can be implemented by a store
+// driver to list and count the keys that matches a glob pattern,
+// see Match for the syntax.
+type MatchDriver interface {
+	Driver
+
+	// KeysMatch returns the keys that matches the pattern.
+	KeysMatch(pattern string) ([]string, error)
+
+	// CountMatch returns the number of keys that matches the pattern.
+	CountMatch(pattern string) (int64, error)
+}
+
+// KV is a key and value pair.
+type KV struct {
+	Key   string
+	Value interface{}
+}
+
+// RangeDriver is the interface that can be implemented by a store
+// driver to read keys and values in byte order of the keys.
+type RangeDriver interface {
+	Driver
+
+	// Range returns up to limit keys and values where start <= key < end
+	// in byte order, or in reverse order if reverse is true. A empty end
+	// has no upper bound and a limit less than one returns all pairs.
+	Range(start, end string, limit int, reverse bool) ([]KV, error)
+}
+
+// CASDriver is the interface that can be implemented by a store
+// driver to support compare-and-set with per-key versions.
+type CASDriver interface {
+	Driver
+
+	// GetWithVersion returns the value and version for a key. The
+	// version is opaque and changes when the value is changed.
+	GetWithVersion(key string, args ...interface{}) (interface{}, string, error)
+
+	// CompareAndSet sets key to value if the version of the key is
+	// the given version, a empty version requires that the key does
+	// not exist. ErrConflict is returned if the version differs.
+	CompareAndSet(key, version string, value interface{}) error
+}
+
+// CounterDriver is the interface that can be implemented by a store
+// driver to atomically increment numeric values. The values is stored
+// with the codec and can be read with Get.
+type CounterDriver interface {
+	Driver
+
+	// IncrBy adds delta to the integer value of key and returns the
+	// new value. A key that does not exist is set to delta.
+	IncrBy(key string, delta int64) (int64, error)
+
+	// IncrByFloat adds delta to the float value of key and returns
+	// the new value. A key that does not exist is set to delta.
+	IncrByFloat(key string, delta float64) (float64, error)
+}
+
+// ConditionalDriver is the interface that can be implemented by a
+// store driver to set keys depending on if they exist, the check and
+// the write is done atomically.
+type ConditionalDriver interface {
+	Driver
+
+	// SetIfAbsent sets key to value if the key does not exist
+	// and reports if the value was set.
+	SetIfAbsent(key string, value interface{}) (bool, error)
+
+	// SetIfPresent sets key to value if the key exist
+	// and reports if the value was set.
+	SetIfPresent(key string, value interface{}) (bool, error)
+}
+
+// WatchDriver is the interface that can be implemented by a store
+// driver to notify about changes of keys.
+type WatchDriver interface {
+	Driver
+
+	// Watch returns a channel that receives events for keys with
+	// the prefix until the context is done, then the channel is
+	// closed. Flush events is sent to every watcher.
+	Watch(ctx context.Context, prefix string) (<-chan Event, error)
+}
+
+// NamespaceDriver is the interface that can be implemented by a
+// store driver that has native namespaces, such as buckets or tables.
+type NamespaceDriver interface {
+	Driver
+
+	// Namespace returns a driver that shares the connection but
+	// only sees the keys in the namespace, so Keys, Count and Flush
+	// is scoped to the namespace.
+	Namespace(name string) (Driver, error)
+}
+
+// PrefixDriver is the interface that can be implemented by a store
+// driver that can prefix the keys itself, e.g. to scope Keys, Count
+// and Flush on the server.
+type PrefixDriver interface {
+	Driver
+
+	// Prefix returns a driver that shares the connection but
+	// prefixes all keys with prefix, so Keys, Count and Flush
+	// only sees the keys with the prefix.
+	Prefix(prefix string) (Driver, error)
+}
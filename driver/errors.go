@@ -0,0 +1,116 @@
+package driver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+var (
+	// ErrNotFound is returned when a key does not exist in the store.
+	ErrNotFound = errors.New("store: key not found")
+
+	// ErrClosed is returned when a store is used after it has been closed.
+	ErrClosed = errors.New("store: store is closed")
+
+	// ErrInvalidArgs is returned when a driver is called with arguments
+	// it can not handle.
+	ErrInvalidArgs = errors.New("store: invalid arguments")
+
+	// ErrConflict is returned when a transaction could not be
+	// committed since a key it read was changed by someone else.
+	ErrConflict = errors.New("store: transaction conflict")
+
+	// ErrNotSupported is returned when a optional feature
+	// is used that the driver does not support.
+	ErrNotSupported = errors.New("store: not supported")
+)
+
+// Error records a failed store operation together with
+// the key and driver name it failed for.
+type Error struct {
+	// Op is the operation that failed, e.g. "get" or "set".
+	Op string
+
+	// Key is the key the operation failed for, if any.
+	Key string
+
+	// Driver is the name of the driver that returned the error.
+	Driver string
+
+	// Err is the underlying error, one of the Err variables
+	// in this package or the error from the backend.
+	Err error
+
+	// Temporary is set by drivers for backend errors
+	// that is known to be temporary.
+	Temporary bool
+}
+
+// Error returns the error message.
+func (e *Error) Error() string {
+	if len(e.Key) == 0 {
+		return fmt.Sprintf("%s %s: %v", e.Driver, e.Op, e.Err)
+	}
+
+	return fmt.Sprintf("%s %s %q: %v", e.Driver, e.Op, e.Key, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Retriable reports whether the operation may succeed if it is retried,
+// e.g. after a timeout or a lost connection.
+func (e *Error) Retriable() bool {
+	if e.Temporary {
+		return true
+	}
+
+	switch {
+	case errors.Is(e.Err, ErrNotFound), errors.Is(e.Err, ErrClosed), errors.Is(e.Err, ErrInvalidArgs), errors.Is(e.Err, ErrNotSupported):
+		return false
+	case errors.Is(e.Err, ErrConflict), errors.Is(e.Err, context.DeadlineExceeded):
+		return true
+	}
+
+	var timeout interface{ Timeout() bool }
+
+	if errors.As(e.Err, &timeout) && timeout.Timeout() {
+		return true
+	}
+
+	var temporary interface{ Temporary() bool }
+
+	return errors.As(e.Err, &temporary) && temporary.Temporary()
+}
+
+// ArgError returns the error for the Open argument at position i
+// of the named driver when it's not of the wanted type.
+func ArgError(name string, i int, want string, got interface{}) error {
+	return &Error{
+		Op:     "open",
+		Driver: name,
+		Err:    fmt.Errorf("argument %d must be %s, got %T: %w", i, want, got, ErrInvalidArgs),
+	}
+}
+
+// CheckArgs returns ErrInvalidArgs if the optional Get args
+// is something else than a non-nil pointer to decode into.
+func CheckArgs(args []interface{}) error {
+	if len(args) == 0 {
+		return nil
+	}
+
+	if len(args) > 1 {
+		return ErrInvalidArgs
+	}
+
+	if v := reflect.ValueOf(args[0]); v.Kind() != reflect.Ptr || v.IsNil() {
+		return ErrInvalidArgs
+	}
+
+	return nil
+}
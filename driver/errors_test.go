@@ -0,0 +1,44 @@
+package driver
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/frozzare/go-assert"
+)
+
+func TestError(t *testing.T) {
+	var err error = &Error{Op: "get", Key: "name", Driver: "rwmutex", Err: ErrNotFound}
+
+	assert.Equal(t, `rwmutex get "name": store: key not found`, err.Error())
+	assert.True(t, errors.Is(err, ErrNotFound))
+	assert.False(t, errors.Is(err, ErrClosed))
+
+	err = &Error{Op: "flush", Driver: "rwmutex", Err: ErrClosed}
+	assert.Equal(t, `rwmutex flush: store: store is closed`, err.Error())
+}
+
+func TestErrorRetriable(t *testing.T) {
+	assert.False(t, (&Error{Err: ErrNotFound}).Retriable())
+	assert.False(t, (&Error{Err: errors.New("error")}).Retriable())
+	assert.True(t, (&Error{Err: context.DeadlineExceeded}).Retriable())
+	assert.True(t, (&Error{Err: ErrConflict}).Retriable())
+	assert.True(t, (&Error{Err: errors.New("error"), Temporary: true}).Retriable())
+}
+
+func TestCheckArgs(t *testing.T) {
+	var s string
+
+	assert.Nil(t, CheckArgs(nil))
+	assert.Nil(t, CheckArgs([]interface{}{&s}))
+	assert.Equal(t, ErrInvalidArgs, CheckArgs([]interface{}{s}))
+	assert.Equal(t, ErrInvalidArgs, CheckArgs([]interface{}{&s, &s}))
+}
+
+func TestArgError(t *testing.T) {
+	err := ArgError("boltdb", 1, "os.FileMode", "0600")
+
+	assert.Equal(t, `boltdb open: argument 1 must be os.FileMode, got string: store: invalid arguments`, err.Error())
+	assert.True(t, errors.Is(err, ErrInvalidArgs))
+}
@@ -0,0 +1,138 @@
+package driver
+
+import "unicode/utf8"
+
+// Match reports whether the key matches the glob pattern. The syntax
+// is the same as for the Redis KEYS and SCAN commands: * matches any
+// sequence of characters, ? matches any single character, [abc] matches
+// one of the characters in the brackets, [^abc] any character but them,
+// [a-z] a character in the range and \x matches x.
+func Match(pattern, key string) bool {
+	p, k := 0, 0
+	star, next := -1, 0
+
+	for p < len(pattern) || k < len(key) {
+		if p < len(pattern) {
+			if pattern[p] == '*' {
+				star, next = p, k
+				p++
+				continue
+			}
+
+			if k < len(key) {
+				r, n := utf8.DecodeRuneInString(key[k:])
+
+				if ok, w := matchOne(pattern[p:], r); ok {
+					p += w
+					k += n
+					continue
+				}
+			}
+		}
+
+		// Let the last star match one more character and try again.
+		if star >= 0 && next < len(key) {
+			_, n := utf8.DecodeRuneInString(key[next:])
+			next += n
+			p, k = star+1, next
+			continue
+		}
+
+		return false
+	}
+
+	return true
+}
+
+// matchOne reports whether r matches the first part of the
+// pattern and returns the width of that part.
+func matchOne(pattern string, r rune) (bool, int) {
+	switch pattern[0] {
+	case '?':
+		return true, 1
+	case '\\':
+		if len(pattern) > 1 {
+			c, n := utf8.DecodeRuneInString(pattern[1:])
+			return c == r, n + 1
+		}
+	case '[':
+		if ok, w := matchClass(pattern, r); w > 0 {
+			return ok, w
+		}
+	}
+
+	c, n := utf8.DecodeRuneInString(pattern)
+
+	return c == r, n
+}
+
+// matchClass reports whether r matches the character class the
+// pattern starts with and returns the width of the class. The width
+// is zero if the class is not closed, the bracket is a literal then.
+func matchClass(pattern string, r rune) (bool, int) {
+	i := 1
+	negate := false
+
+	if i < len(pattern) && (pattern[i] == '^' || pattern[i] == '!') {
+		negate = true
+		i++
+	}
+
+	matched := false
+
+	for first := true; i < len(pattern); first = false {
+		if pattern[i] == ']' && !first {
+			return matched != negate, i + 1
+		}
+
+		if pattern[i] == '\\' && i+1 < len(pattern) {
+			i++
+		}
+
+		lo, n := utf8.DecodeRuneInString(pattern[i:])
+		i += n
+		hi := lo
+
+		if i+1 < len(pattern) && pattern[i] == '-' && pattern[i+1] != ']' {
+			i++
+
+			if pattern[i] == '\\' && i+1 < len(pattern) {
+				i++
+			}
+
+			hi, n = utf8.DecodeRuneInString(pattern[i:])
+			i += n
+		}
+
+		if lo > hi {
+			lo, hi = hi, lo
+		}
+
+		if lo <= r && r <= hi {
+			matched = true
+		}
+	}
+
+	return false, 0
+}
+
+// MatchPrefix returns the literal prefix of the glob pattern, all
+// keys that matches the pattern starts with it.
+func MatchPrefix(pattern string) string {
+	var prefix []byte
+
+	for i := 0; i < len(pattern); i++ {
+		switch pattern[i] {
+		case '*', '?', '[':
+			return string(prefix)
+		case '\\':
+			if i+1 < len(pattern) {
+				i++
+			}
+		}
+
+		prefix = append(prefix, pattern[i])
+	}
+
+	return string(prefix)
+}
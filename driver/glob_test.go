@@ -0,0 +1,47 @@
+package driver
+
+import (
+	"testing"
+
+	"github.com/frozzare/go-assert"
+)
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		key     string
+		match   bool
+	}{
+		{"*", "", true},
+		{"*", "session:1", true},
+		{"session:*", "session:1", true},
+		{"session:*", "user:1", false},
+		{"user:?:profile", "user:1:profile", true},
+		{"user:?:profile", "user:12:profile", false},
+		{"user:*:profile", "user:12:profile", true},
+		{"*:profile", "user:1:settings", false},
+		{"h?llo", "héllo", true},
+		{"h[ae]llo", "hallo", true},
+		{"h[ae]llo", "hillo", false},
+		{"h[^e]llo", "hallo", true},
+		{"h[^e]llo", "hello", false},
+		{"h[a-c]llo", "hbllo", true},
+		{"h[a-c]llo", "hdllo", false},
+		{`h\*llo`, "h*llo", true},
+		{`h\*llo`, "hello", false},
+		{"h[llo", "h[llo", true},
+		{"a*b*c", "axxbyyc", true},
+		{"a*b*c", "axxbyy", false},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.match, Match(test.pattern, test.key))
+	}
+}
+
+func TestMatchPrefix(t *testing.T) {
+	assert.Equal(t, "session:", MatchPrefix("session:*"))
+	assert.Equal(t, "user:", MatchPrefix("user:?:profile"))
+	assert.Equal(t, "h*llo", MatchPrefix(`h\*llo`))
+	assert.Equal(t, "", MatchPrefix("*"))
+}
@@ -0,0 +1,97 @@
+package driver
+
+import (
+	"sync"
+	"time"
+)
+
+// JanitorInterval is how often drivers that emulates key
+// expiration removes expired keys in the background.
+var JanitorInterval = time.Minute
+
+// Janitor removes expired keys in the background for drivers
+// that emulates key expiration.
+type Janitor struct {
+	// Interval is how often Purge is called,
+	// JanitorInterval is used if zero.
+	Interval time.Duration
+
+	// Purge removes the expired keys and reports if there
+	// is keys left that will expire. The janitor stops when
+	// there is nothing left to expire.
+	Purge func() bool
+
+	lock    sync.Mutex
+	running bool
+	pending bool
+	stop    chan struct{}
+}
+
+// Start starts the janitor if it's not already running.
+func (j *Janitor) Start() {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	if j.running {
+		j.pending = true
+		return
+	}
+
+	interval := j.Interval
+	if interval <= 0 {
+		interval = JanitorInterval
+	}
+
+	j.running = true
+	j.stop = make(chan struct{})
+
+	go j.run(interval, j.stop)
+}
+
+// run calls Purge every interval until there is
+// nothing left to expire or the janitor is stopped.
+func (j *Janitor) run(interval time.Duration, stop chan struct{}) {
+	ticker := time.NewTicker(interval)
+
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			j.lock.Lock()
+			j.pending = false
+			j.lock.Unlock()
+
+			if j.Purge() {
+				continue
+			}
+
+			j.lock.Lock()
+
+			// Keep running if a key with a ttl was
+			// added while purging.
+			if j.pending || j.stop != stop {
+				j.lock.Unlock()
+				continue
+			}
+
+			j.running = false
+			j.lock.Unlock()
+
+			return
+		}
+	}
+}
+
+// Stop stops the janitor if it's running.
+func (j *Janitor) Stop() {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	if j.running {
+		close(j.stop)
+		j.running = false
+	}
+}
@@ -0,0 +1,33 @@
+package driver
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/frozzare/go-assert"
+)
+
+func TestJanitor(t *testing.T) {
+	var calls int32
+
+	j := &Janitor{
+		Interval: 10 * time.Millisecond,
+		Purge: func() bool {
+			return atomic.AddInt32(&calls, 1) < 3
+		},
+	}
+
+	j.Start()
+	j.Start()
+	time.Sleep(100 * time.Millisecond)
+
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+
+	j.Start()
+	j.Stop()
+	j.Stop()
+	time.Sleep(50 * time.Millisecond)
+
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+}
@@ -0,0 +1,95 @@
+package driver
+
+import (
+	"sort"
+	"sync"
+)
+
+// Factory opens a driver with the Open args.
+type Factory func(args ...interface{}) (Driver, error)
+
+// factory is a registered Factory and the
+// default args it's called with.
+type factory struct {
+	open     Factory
+	defaults []interface{}
+}
+
+var (
+	factoriesMu sync.RWMutex
+	factories   = make(map[string]factory)
+)
+
+// Register makes a driver available by the provided name, the driver's
+// Open method is used to open it. If Register is called twice with the
+// same name or if driver is nil, it panics.
+func Register(name string, driver Driver) {
+	if driver == nil {
+		panic("store: Register driver is nil")
+	}
+
+	RegisterFactory(name, driver.Open)
+}
+
+// RegisterFactory makes a driver available by the provided name. The
+// defaults is passed to open before the Open args, so the args can
+// override them. If RegisterFactory is called twice with the same name
+// or if open is nil, it panics.
+func RegisterFactory(name string, open Factory, defaults ...interface{}) {
+	factoriesMu.Lock()
+	defer factoriesMu.Unlock()
+
+	if open == nil {
+		panic("store: Register driver is nil")
+	}
+
+	if _, dup := factories[name]; dup {
+		panic("store: Register called twice for driver " + name)
+	}
+
+	factories[name] = factory{open: open, defaults: defaults}
+}
+
+// Unregister removes the driver registered by the provided name,
+// it's mostly useful in tests.
+func Unregister(name string) {
+	factoriesMu.Lock()
+	defer factoriesMu.Unlock()
+
+	delete(factories, name)
+}
+
+// Drivers returns a sorted list of the names of the registered drivers.
+func Drivers() []string {
+	factoriesMu.RLock()
+	defer factoriesMu.RUnlock()
+
+	names := make([]string, 0, len(factories))
+
+	for name := range factories {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// Lookup returns a Factory for the driver registered by the
+// provided name that is called with the registered defaults.
+func Lookup(name string) (Factory, bool) {
+	factoriesMu.RLock()
+	f, ok := factories[name]
+	factoriesMu.RUnlock()
+
+	if !ok {
+		return nil, false
+	}
+
+	return func(args ...interface{}) (Driver, error) {
+		all := make([]interface{}, 0, len(f.defaults)+len(args))
+		all = append(all, f.defaults...)
+
+		return f.open(append(all, args...)...)
+	}, true
+}
@@ -0,0 +1,34 @@
+package driver
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/frozzare/go-assert"
+)
+
+func TestRegisterFactory(t *testing.T) {
+	var got []interface{}
+
+	RegisterFactory("factory", func(args ...interface{}) (Driver, error) {
+		got = args
+		return nil, nil
+	}, "default")
+
+	defer Unregister("factory")
+
+	names := Drivers()
+	i := sort.SearchStrings(names, "factory")
+	assert.True(t, i < len(names) && names[i] == "factory")
+
+	open, ok := Lookup("factory")
+	assert.True(t, ok)
+
+	open("arg")
+	assert.Equal(t, []interface{}{"default", "arg"}, got)
+
+	Unregister("factory")
+
+	_, ok = Lookup("factory")
+	assert.False(t, ok)
+}
@@ -0,0 +1,148 @@
+package driver
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/frozzare/go-store/codec"
+)
+
+// URLOpener opens a driver from a URL with the scheme it's registered for.
+type URLOpener func(u *url.URL) (Driver, error)
+
+var (
+	urlOpenersMu sync.RWMutex
+	urlOpeners   = make(map[string]URLOpener)
+)
+
+// RegisterURL makes a URL scheme available to store.OpenURL.
+// If RegisterURL is called twice with the same scheme or if
+// open is nil, it panics.
+func RegisterURL(scheme string, open URLOpener) {
+	urlOpenersMu.Lock()
+	defer urlOpenersMu.Unlock()
+
+	if open == nil {
+		panic("store: RegisterURL opener is nil")
+	}
+
+	if _, dup := urlOpeners[scheme]; dup {
+		panic("store: RegisterURL called twice for scheme " + scheme)
+	}
+
+	urlOpeners[scheme] = open
+}
+
+// LookupURL returns the opener registered for the scheme.
+func LookupURL(scheme string) (URLOpener, bool) {
+	urlOpenersMu.RLock()
+	defer urlOpenersMu.RUnlock()
+
+	open, ok := urlOpeners[scheme]
+
+	return open, ok
+}
+
+// URLPath returns the file path of a URL. The host is part of the
+// path, so both leveldb:///var/lib/app and leveldb://app works.
+func URLPath(u *url.URL) string {
+	return u.Host + u.Path
+}
+
+// URLParams returns a error if the URL has a query
+// parameter that is not one of the allowed.
+func URLParams(u *url.URL, allowed ...string) error {
+	for name := range u.Query() {
+		found := false
+
+		for _, a := range allowed {
+			if name == a {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return fmt.Errorf("store: unknown %s url parameter %q: %w", u.Scheme, name, ErrInvalidArgs)
+		}
+	}
+
+	return nil
+}
+
+// URLDuration returns the duration of a query parameter,
+// zero is returned if the parameter is missing.
+func URLDuration(u *url.URL, name string) (time.Duration, error) {
+	value := u.Query().Get(name)
+
+	if value == "" {
+		return 0, nil
+	}
+
+	d, err := time.ParseDuration(value)
+
+	if err != nil {
+		return 0, fmt.Errorf("store: invalid %s url parameter %s=%q: %w", u.Scheme, name, value, ErrInvalidArgs)
+	}
+
+	return d, nil
+}
+
+// URLInt returns the integer of a query parameter,
+// zero is returned if the parameter is missing.
+func URLInt(u *url.URL, name string) (int, error) {
+	value := u.Query().Get(name)
+
+	if value == "" {
+		return 0, nil
+	}
+
+	n, err := strconv.Atoi(value)
+
+	if err != nil {
+		return 0, fmt.Errorf("store: invalid %s url parameter %s=%q: %w", u.Scheme, name, value, ErrInvalidArgs)
+	}
+
+	return n, nil
+}
+
+// URLBool returns the boolean of a query parameter,
+// false is returned if the parameter is missing.
+func URLBool(u *url.URL, name string) (bool, error) {
+	value := u.Query().Get(name)
+
+	if value == "" {
+		return false, nil
+	}
+
+	b, err := strconv.ParseBool(value)
+
+	if err != nil {
+		return false, fmt.Errorf("store: invalid %s url parameter %s=%q: %w", u.Scheme, name, value, ErrInvalidArgs)
+	}
+
+	return b, nil
+}
+
+// URLCodec returns the codec named by the codec query parameter, one
+// of json, gob, msgpack, raw and envelope. JSON is used if missing.
+func URLCodec(u *url.URL) (Codec, error) {
+	switch name := strings.ToLower(u.Query().Get("codec")); name {
+	case "", "json":
+		return codec.JSON{}, nil
+	case "gob":
+		return codec.Gob{}, nil
+	case "msgpack":
+		return codec.Msgpack{}, nil
+	case "raw":
+		return codec.Raw{}, nil
+	case "envelope":
+		return codec.Envelope{}, nil
+	default:
+		return nil, fmt.Errorf("store: unknown codec %q: %w", name, ErrInvalidArgs)
+	}
+}
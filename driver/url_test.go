@@ -0,0 +1,38 @@
+package driver
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/frozzare/go-assert"
+	"github.com/frozzare/go-store/codec"
+)
+
+func TestURLHelpers(t *testing.T) {
+	u, _ := url.Parse("bolt://var/app.db?timeout=1s&size=10&sync=true&codec=gob")
+
+	assert.Equal(t, "var/app.db", URLPath(u))
+	assert.Nil(t, URLParams(u, "timeout", "size", "sync", "codec"))
+	assert.True(t, errors.Is(URLParams(u, "timeout"), ErrInvalidArgs))
+
+	d, err := URLDuration(u, "timeout")
+	assert.Nil(t, err)
+	assert.Equal(t, time.Second, d)
+
+	n, err := URLInt(u, "size")
+	assert.Nil(t, err)
+	assert.Equal(t, 10, n)
+
+	b, err := URLBool(u, "sync")
+	assert.Nil(t, err)
+	assert.True(t, b)
+
+	c, err := URLCodec(u)
+	assert.Nil(t, err)
+	assert.Equal(t, codec.Gob{}, c)
+
+	_, err = URLInt(u, "timeout")
+	assert.True(t, errors.Is(err, ErrInvalidArgs))
+}
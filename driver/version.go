@@ -0,0 +1,19 @@
+package driver
+
+import (
+	"hash/fnv"
+	"strconv"
+)
+
+// Version returns the version for the stored data of a key. It's a
+// hash of the data, so the version changes when the value is changed
+// and a empty version is never returned. A value that is changed and
+// then changed back gets the same version again, so a driver that can
+// store a version next to the value should use a counter instead, as
+// the bundled drivers does.
+func Version(data []byte) string {
+	h := fnv.New64a()
+	h.Write(data)
+
+	return strconv.FormatUint(h.Sum64(), 36)
+}
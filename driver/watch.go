@@ -0,0 +1,160 @@
+package driver
+
+import (
+	"context"
+	"strings"
+	"sync"
+)
+
+// EventType is the type of a change event.
+type EventType int
+
+const (
+	// EventSet is sent when a key is set.
+	EventSet EventType = iota + 1
+
+	// EventDelete is sent when a key is deleted.
+	EventDelete
+
+	// EventExpire is sent when a key is removed because it expired.
+	EventExpire
+
+	// EventFlush is sent when all keys is removed.
+	EventFlush
+)
+
+// String returns the name of the event type.
+func (t EventType) String() string {
+	switch t {
+	case EventSet:
+		return "set"
+	case EventDelete:
+		return "delete"
+	case EventExpire:
+		return "expire"
+	case EventFlush:
+		return "flush"
+	}
+
+	return "unknown"
+}
+
+// Event describes a change in a store, Key is empty for EventFlush.
+type Event struct {
+	Type EventType
+	Key  string
+}
+
+// WatchBuffer is how many events is buffered for each watcher.
+// Events for a watcher that is not keeping up are dropped, so a
+// slow watcher never blocks the writes.
+var WatchBuffer = 128
+
+// watcher is a channel that receives events for a prefix.
+type watcher struct {
+	prefix string
+	events chan Event
+}
+
+// Hub fans out events to the watchers of a driver in the same
+// process. The zero value is ready to use.
+type Hub struct {
+	lock     sync.Mutex
+	watchers map[*watcher]struct{}
+	closed   bool
+	done     chan struct{}
+}
+
+// Watch returns a channel that receives the events for keys with
+// the prefix until the context is done or the hub is closed.
+func (h *Hub) Watch(ctx context.Context, prefix string) <-chan Event {
+	w := &watcher{prefix: prefix, events: make(chan Event, WatchBuffer)}
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	if h.closed {
+		close(w.events)
+		return w.events
+	}
+
+	if h.watchers == nil {
+		h.watchers = make(map[*watcher]struct{})
+		h.done = make(chan struct{})
+	}
+
+	h.watchers[w] = struct{}{}
+
+	go func(done chan struct{}) {
+		select {
+		case <-ctx.Done():
+		case <-done:
+		}
+
+		h.remove(w)
+	}(h.done)
+
+	return w.events
+}
+
+// remove removes the watcher and closes its channel.
+func (h *Hub) remove(w *watcher) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	if _, ok := h.watchers[w]; ok {
+		delete(h.watchers, w)
+		close(w.events)
+	}
+}
+
+// Publish sends a event for each key to the watchers of the keys.
+// Nothing is sent to watchers whose buffer is full.
+func (h *Hub) Publish(typ EventType, keys ...string) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	if len(h.watchers) == 0 {
+		return
+	}
+
+	if typ == EventFlush {
+		keys = []string{""}
+	}
+
+	for _, key := range keys {
+		for w := range h.watchers {
+			if typ != EventFlush && !strings.HasPrefix(key, w.prefix) {
+				continue
+			}
+
+			select {
+			case w.events <- Event{Type: typ, Key: key}:
+			default:
+			}
+		}
+	}
+}
+
+// Close closes the channels of all watchers,
+// later watchers get a closed channel.
+func (h *Hub) Close() {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	if h.closed {
+		return
+	}
+
+	h.closed = true
+
+	for w := range h.watchers {
+		close(w.events)
+	}
+
+	h.watchers = nil
+
+	if h.done != nil {
+		close(h.done)
+	}
+}
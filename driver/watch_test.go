@@ -0,0 +1,50 @@
+package driver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/frozzare/go-assert"
+)
+
+func TestHub(t *testing.T) {
+	var h Hub
+
+	ctx, cancel := context.WithCancel(context.Background())
+	users := h.Watch(ctx, "user:")
+	all := h.Watch(context.Background(), "")
+
+	h.Publish(EventSet, "user:1", "post:1")
+	h.Publish(EventFlush)
+
+	assert.Equal(t, Event{Type: EventSet, Key: "user:1"}, <-users)
+	assert.Equal(t, Event{Type: EventFlush}, <-users)
+	assert.Equal(t, Event{Type: EventSet, Key: "user:1"}, <-all)
+	assert.Equal(t, Event{Type: EventSet, Key: "post:1"}, <-all)
+	assert.Equal(t, Event{Type: EventFlush}, <-all)
+
+	cancel()
+	_, ok := <-users
+	assert.False(t, ok)
+
+	h.Close()
+	_, ok = <-all
+	assert.False(t, ok)
+
+	_, ok = <-h.Watch(context.Background(), "")
+	assert.False(t, ok)
+}
+
+func TestHubSlowWatcher(t *testing.T) {
+	var h Hub
+
+	events := h.Watch(context.Background(), "")
+
+	for i := 0; i < WatchBuffer*2; i++ {
+		h.Publish(EventSet, "key")
+	}
+
+	assert.Equal(t, WatchBuffer, len(events))
+
+	h.Close()
+}
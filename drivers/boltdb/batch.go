@@ -0,0 +1,136 @@
+package boltdb
+
+import (
+	"time"
+
+	"github.com/boltdb/bolt"
+	"github.com/frozzare/go-store/driver"
+)
+
+// GetMulti returns the values for the keys that exists in store.
+func (s *Driver) GetMulti(keys []string, args ...interface{}) (values map[string]interface{}, err error) {
+	if err := driver.CheckArgs(args); err != nil {
+		return nil, wrapError("getmulti", "", err)
+	}
+
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	db, err := s.db()
+
+	if err != nil {
+		return nil, wrapError("getmulti", "", err)
+	}
+
+	err = db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(s.bucket))
+		now := time.Now()
+
+		values, err = driver.DecodeMulti(keys, args, func(key string, v interface{}) error {
+			if bucket == nil {
+				return driver.ErrNotFound
+			}
+
+			res := bucket.Get([]byte(key))
+			if res == nil || s.expired(tx, []byte(key), now) {
+				return driver.ErrNotFound
+			}
+
+			return driver.Unmarshal(s.codec, res, v)
+		})
+
+		return err
+	})
+
+	if err != nil {
+		return nil, wrapError("getmulti", "", err)
+	}
+
+	return
+}
+
+// SetMulti keys with values in store.
+func (s *Driver) SetMulti(values map[string]interface{}) error {
+	data := make(map[string][]byte, len(values))
+
+	for key, value := range values {
+		b, err := s.codec.Marshal(value)
+
+		if err != nil {
+			return wrapError("setmulti", key, err)
+		}
+
+		data[key] = b
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	db, err := s.db()
+
+	if err != nil {
+		return wrapError("setmulti", "", err)
+	}
+
+	return wrapError("setmulti", "", db.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists([]byte(s.bucket))
+		if err != nil {
+			return err
+		}
+
+		for key, b := range data {
+			s.publish(tx, driver.EventSet, key)
+
+			if err := s.persist(tx, []byte(key)); err != nil {
+				return err
+			}
+
+			if err := s.bump(tx, []byte(key)); err != nil {
+				return err
+			}
+
+			if err := bucket.Put([]byte(key), b); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}))
+}
+
+// DeleteMulti keys from store.
+func (s *Driver) DeleteMulti(keys ...string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	db, err := s.db()
+
+	if err != nil {
+		return wrapError("deletemulti", "", err)
+	}
+
+	return wrapError("deletemulti", "", db.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists([]byte(s.bucket))
+		if err != nil {
+			return err
+		}
+
+		for _, key := range keys {
+			s.publish(tx, driver.EventDelete, key)
+
+			if err := bucket.Delete([]byte(key)); err != nil {
+				return err
+			}
+
+			if err := s.unversion(tx, []byte(key)); err != nil {
+				return err
+			}
+
+			if err := s.persist(tx, []byte(key)); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}))
+}
@@ -1,13 +1,12 @@
 package boltdb
 
 import (
-	"bytes"
+	"context"
 	"crypto/md5"
-	"encoding/json"
+	"errors"
 	"fmt"
-	"reflect"
-
-	"os"
+	"sync"
+	"time"
 
 	"github.com/boltdb/bolt"
 	"github.com/frozzare/go-store/driver"
@@ -15,55 +14,94 @@ import (
 
 // Driver represents a BoltDB driver.
 type Driver struct {
-	args   []interface{}
-	bucket string
-	closed bool
-	client *bolt.DB
+	config  Config
+	bucket  string
+	closed  bool
+	client  *bolt.DB
+	codec   driver.Codec
+	janitor *driver.Janitor
+	hub     driver.Hub
+
+	// lock is read locked by the calls that reads and write locked
+	// by the calls that writes, so Close waits for the calls in
+	// flight. It's shared with the namespaces of the driver.
+	lock *sync.RWMutex
+
+	// open serializes the calls that opens the client.
+	open sync.Mutex
+
+	// root is the driver that owns the client if this
+	// driver is a namespace.
+	root *Driver
+
+	// janitors is the janitors of the namespaces by bucket, so
+	// Close can stop them. It's guarded by the open mutex.
+	janitors map[string]*driver.Janitor
 }
 
 // db returns the BoltDB client if existing
-// or creating a new if closed.
+// or opening it on the first call, ErrClosed is
+// returned after Close.
 func (s *Driver) db() (*bolt.DB, error) {
-	if s.client != nil && !s.closed {
-		return s.client, nil
-	}
+	if s.root != nil {
+		if s.closed {
+			return nil, driver.ErrClosed
+		}
 
-	s.closed = false
+		return s.root.db()
+	}
 
-	path := "/tmp/store-bolt.db"
-	mode := os.FileMode(0600)
-	options := &bolt.Options{}
+	s.open.Lock()
+	defer s.open.Unlock()
 
-	if len(s.args) > 0 && s.args[0] != nil {
-		path = s.args[0].(string)
+	if s.closed {
+		return nil, driver.ErrClosed
 	}
 
-	if len(s.args) > 1 && s.args[1] != nil {
-		mode = s.args[1].(os.FileMode)
+	if s.client != nil {
+		return s.client, nil
 	}
 
-	if len(s.args) > 2 && s.args[2] != nil {
-		options = s.args[2].(*bolt.Options)
+	client, err := bolt.Open(s.config.Path, s.config.Mode, s.config.Options)
+
+	if err != nil {
+		return nil, err
 	}
 
-	client, err := bolt.Open(path, mode, options)
+	s.client = client
+
+	return client, nil
+}
+
+// init registers the driver with the name boltdb.
+func init() {
+	driver.RegisterFactory("boltdb", Open)
+}
+
+// Open creates a new BoltDB store. The args is a Config, Options
+// such as WithBucket, a driver.Codec or the positional path, mode
+// and *bolt.Options.
+func Open(args ...interface{}) (driver.Driver, error) {
+	config, err := newConfig(args)
 
 	if err != nil {
 		return nil, err
 	}
 
-	s.client = client
+	s := &Driver{
+		config: config,
+		bucket: config.Bucket,
+		codec:  config.Codec,
+		lock:   new(sync.RWMutex),
+	}
 
 	if len(s.bucket) == 0 {
-		s.bucket = fmt.Sprintf("%x", md5.Sum([]byte(path)))
+		s.bucket = fmt.Sprintf("%x", md5.Sum([]byte(config.Path)))
 	}
 
-	return client, nil
-}
+	s.janitor = &driver.Janitor{Purge: s.purge}
 
-// Open creates a new BoltDB store.
-func Open(args ...interface{}) (driver.Driver, error) {
-	return &Driver{args: args}, nil
+	return s, nil
 }
 
 // Open creates a new BoltDB store with a specified instance.
@@ -71,28 +109,64 @@ func (s *Driver) Open(args ...interface{}) (driver.Driver, error) {
 	return Open(args...)
 }
 
+// Capabilities returns the optional features the driver supports.
+func (s *Driver) Capabilities() driver.Capabilities {
+	return driver.CapContext | driver.CapTTL | driver.CapBatch | driver.CapTx |
+		driver.CapScan | driver.CapMatch | driver.CapRange | driver.CapCAS |
+		driver.CapCounter | driver.CapConditional | driver.CapWatch | driver.CapNamespace
+}
+
+// wrapError maps BoltDB errors onto the driver errors
+// and records the operation and key.
+func wrapError(op, key string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	e := &driver.Error{Op: op, Key: key, Driver: "boltdb", Err: err}
+
+	switch err {
+	case bolt.ErrDatabaseNotOpen:
+		e.Err = driver.ErrClosed
+	case bolt.ErrTimeout:
+		e.Temporary = true
+	}
+
+	return e
+}
+
 // Count returns numbers of keys in store.
-func (s *Driver) Count() (count int64, err error) {
-	defer s.Close()
+func (s *Driver) Count() (int64, error) {
+	return s.CountContext(context.Background())
+}
+
+// CountContext returns numbers of keys in store.
+func (s *Driver) CountContext(ctx context.Context) (count int64, err error) {
+	if err := ctx.Err(); err != nil {
+		return 0, wrapError("count", "", err)
+	}
+
+	s.lock.RLock()
+	defer s.lock.RUnlock()
 
 	db, err := s.db()
 
 	if err != nil {
-		return 0, err
+		return 0, wrapError("count", "", err)
 	}
 
 	err = db.View(func(tx *bolt.Tx) error {
 		bucket := tx.Bucket([]byte(s.bucket))
 		if bucket == nil {
-			return fmt.Errorf("Bucket %q not found!", []byte(s.bucket))
+			return nil
 		}
 
-		count = int64(bucket.Stats().KeyN)
+		count = int64(bucket.Stats().KeyN) - s.countExpired(tx, time.Now())
 		return nil
 	})
 
 	if err != nil {
-		return 0, err
+		return 0, wrapError("count", "", err)
 	}
 
 	return
@@ -100,137 +174,184 @@ func (s *Driver) Count() (count int64, err error) {
 
 // Exists returns true when a key exists false when not existing in store.
 func (s *Driver) Exists(key string) (bool, error) {
-	value, err := s.Get(key)
+	return s.ExistsContext(context.Background(), key)
+}
+
+// ExistsContext returns true when a key exists false when not existing in store.
+func (s *Driver) ExistsContext(ctx context.Context, key string) (bool, error) {
+	_, err := s.GetContext(ctx, key)
+
+	if errors.Is(err, driver.ErrNotFound) {
+		return false, nil
+	}
 
 	if err != nil {
 		return false, err
 	}
 
-	return value != nil, nil
+	return true, nil
 }
 
 // Keys returns a string slice with all keys.
-func (s *Driver) Keys() (keys []string, err error) {
-	defer s.Close()
+func (s *Driver) Keys() ([]string, error) {
+	return s.KeysContext(context.Background())
+}
+
+// KeysContext returns a string slice with all keys.
+func (s *Driver) KeysContext(ctx context.Context) (keys []string, err error) {
+	if err := ctx.Err(); err != nil {
+		return []string{}, wrapError("keys", "", err)
+	}
+
+	s.lock.RLock()
+	defer s.lock.RUnlock()
 
 	db, err := s.db()
 
 	if err != nil {
-		return []string{}, err
+		return []string{}, wrapError("keys", "", err)
 	}
 
 	err = db.View(func(tx *bolt.Tx) error {
 		bucket := tx.Bucket([]byte(s.bucket))
 		if bucket == nil {
-			return fmt.Errorf("Bucket %q not found!", []byte(s.bucket))
+			return nil
 		}
 
-		bucket.ForEach(func(key []byte, value []byte) error {
-			keys = append(keys, string(key))
+		now := time.Now()
 
-			return nil
-		})
+		return bucket.ForEach(func(key []byte, value []byte) error {
+			if !s.expired(tx, key, now) {
+				keys = append(keys, string(key))
+			}
 
-		return nil
+			return ctx.Err()
+		})
 	})
 
 	if err != nil {
-		return []string{}, err
+		return []string{}, wrapError("keys", "", err)
 	}
 
 	return
 }
 
 // Get returns the value for a key if any.
-func (s *Driver) Get(key string, args ...interface{}) (value interface{}, err error) {
-	defer s.Close()
+func (s *Driver) Get(key string, args ...interface{}) (interface{}, error) {
+	return s.GetContext(context.Background(), key, args...)
+}
+
+// GetContext returns the value for a key if any.
+func (s *Driver) GetContext(ctx context.Context, key string, args ...interface{}) (value interface{}, err error) {
+	if err := ctx.Err(); err != nil {
+		return nil, wrapError("get", key, err)
+	}
+
+	if err := driver.CheckArgs(args); err != nil {
+		return nil, wrapError("get", key, err)
+	}
+
+	s.lock.RLock()
+	defer s.lock.RUnlock()
 
 	db, err := s.db()
 
 	if err != nil {
-		return nil, err
+		return nil, wrapError("get", key, err)
 	}
 
 	err = db.View(func(tx *bolt.Tx) error {
 		bucket := tx.Bucket([]byte(s.bucket))
 		if bucket == nil {
-			return fmt.Errorf("Bucket %q not found!", []byte(s.bucket))
+			return driver.ErrNotFound
 		}
 
-		var buffer bytes.Buffer
-		buffer.Write(bucket.Get([]byte(key)))
-
-		bytes := buffer.Bytes()
-
-		var js interface{}
-
-		if len(args) > 0 {
-			js = args[0]
+		res := bucket.Get([]byte(key))
+		if res == nil || s.expired(tx, []byte(key), time.Now()) {
+			return driver.ErrNotFound
 		}
 
-		if err = json.Unmarshal(bytes, &js); err == nil {
-			if len(args) > 0 {
-				value = nil
-			} else {
-				value = js
-			}
-		} else if len(bytes) > 0 {
-			value = string(bytes)
-		}
+		value, err = driver.Decode(s.codec, res, args)
 
-		return nil
+		return err
 	})
 
+	if err != nil {
+		return nil, wrapError("get", key, err)
+	}
+
 	return
 }
 
 // Set key with value in store.
 func (s *Driver) Set(key string, value interface{}) error {
-	defer s.Close()
+	return s.SetContext(context.Background(), key, value)
+}
+
+// SetContext key with value in store.
+func (s *Driver) SetContext(ctx context.Context, key string, value interface{}) error {
+	if err := ctx.Err(); err != nil {
+		return wrapError("set", key, err)
+	}
+
+	data, err := s.codec.Marshal(value)
+
+	if err != nil {
+		return wrapError("set", key, err)
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
 
 	db, err := s.db()
 
 	if err != nil {
-		return err
+		return wrapError("set", key, err)
 	}
 
-	return db.Update(func(tx *bolt.Tx) error {
+	return wrapError("set", key, db.Update(func(tx *bolt.Tx) error {
+		s.publish(tx, driver.EventSet, key)
+
 		bucket, err := tx.CreateBucketIfNotExists([]byte(s.bucket))
 		if err != nil {
 			return err
 		}
 
-		if reflect.TypeOf(value).Kind() != reflect.String {
-			value, err := json.Marshal(value)
-
-			if err != nil {
-				return err
-			}
-
-			err = bucket.Put([]byte(key), value)
-		} else {
-			err = bucket.Put([]byte(key), []byte(value.(string)))
+		if err := s.persist(tx, []byte(key)); err != nil {
+			return err
 		}
 
-		if err != nil {
+		if err := s.bump(tx, []byte(key)); err != nil {
 			return err
 		}
 
-		return nil
-	})
+		return bucket.Put([]byte(key), data)
+	}))
 }
 
 // Delete key from store.
 func (s *Driver) Delete(key string) error {
-	defer s.Close()
+	return s.DeleteContext(context.Background(), key)
+}
+
+// DeleteContext key from store.
+func (s *Driver) DeleteContext(ctx context.Context, key string) error {
+	if err := ctx.Err(); err != nil {
+		return wrapError("delete", key, err)
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
 
 	db, err := s.db()
 
 	if err != nil {
-		return err
+		return wrapError("delete", key, err)
 	}
 
-	return db.Update(func(tx *bolt.Tx) error {
+	return wrapError("delete", key, db.Update(func(tx *bolt.Tx) error {
+		s.publish(tx, driver.EventDelete, key)
+
 		bucket, err := tx.CreateBucketIfNotExists([]byte(s.bucket))
 		if err != nil {
 			return err
@@ -241,39 +362,94 @@ func (s *Driver) Delete(key string) error {
 			return err
 		}
 
-		return nil
-	})
+		if err := s.unversion(tx, []byte(key)); err != nil {
+			return err
+		}
+
+		return s.persist(tx, []byte(key))
+	}))
 }
 
-// Close will close the boltdb client.
+// Close waits for the calls in flight and closes the database file,
+// the calls made after Close returns ErrClosed. A namespace does not
+// close the file since it's owned by the driver it's created from,
+// but the janitors of the namespaces is stopped with the file.
 func (s *Driver) Close() error {
-	db, err := s.db()
+	err := s.close()
 
-	if err != nil {
-		return err
+	// The janitors is stopped when the lock is released,
+	// since a running purge waits for it.
+	s.janitor.Stop()
+	s.hub.Close()
+
+	if s.root == nil {
+		s.open.Lock()
+		defer s.open.Unlock()
+
+		for _, j := range s.janitors {
+			j.Stop()
+		}
 	}
 
-	err = db.Close()
+	return err
+}
 
-	if err != nil {
-		return err
+// close marks the driver as closed and closes
+// the client if this driver owns it.
+func (s *Driver) close() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.closed {
+		return nil
 	}
 
 	s.closed = true
 
-	return nil
+	if s.root != nil || s.client == nil {
+		return nil
+	}
+
+	return wrapError("close", "", s.client.Close())
 }
 
 // Flush will remove all keys and values from the store.
 func (s *Driver) Flush() error {
-	defer s.Close()
+	return s.FlushContext(context.Background())
+}
+
+// FlushContext will remove all keys and values from the store.
+func (s *Driver) FlushContext(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return wrapError("flush", "", err)
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
 	db, err := s.db()
 
 	if err != nil {
-		return err
+		return wrapError("flush", "", err)
 	}
 
-	return db.Update(func(tx *bolt.Tx) error {
+	err = db.Update(func(tx *bolt.Tx) error {
+		s.publish(tx, driver.EventFlush)
+
+		if err := tx.DeleteBucket(s.ttlBucket()); err != nil && err != bolt.ErrBucketNotFound {
+			return err
+		}
+
+		if err := s.clearVersions(tx); err != nil {
+			return err
+		}
+
 		return tx.DeleteBucket([]byte(s.bucket))
 	})
+
+	if err == bolt.ErrBucketNotFound {
+		return nil
+	}
+
+	return wrapError("flush", "", err)
 }
@@ -0,0 +1,169 @@
+package boltdb
+
+import (
+	"encoding/binary"
+	"strconv"
+	"time"
+
+	"github.com/boltdb/bolt"
+	"github.com/frozzare/go-store/driver"
+)
+
+// versionBucket returns the name of the bucket that holds the
+// version of the keys. The version is the sequence of the bucket
+// when the key was written, the sequence is kept when keys is
+// deleted so a key never gets back a earlier version.
+func (s *Driver) versionBucket() []byte {
+	return []byte(s.bucket + ":version")
+}
+
+// version returns the version of a key or a empty string if the key
+// does not exist. A key written before the versions was stored has
+// the version 0 until it's written again.
+func (s *Driver) version(tx *bolt.Tx, key []byte) string {
+	if !s.exists(tx, key) {
+		return ""
+	}
+
+	var seq uint64
+
+	if bucket := tx.Bucket(s.versionBucket()); bucket != nil {
+		if value := bucket.Get(key); value != nil {
+			seq = binary.BigEndian.Uint64(value)
+		}
+	}
+
+	return strconv.FormatUint(seq, 36)
+}
+
+// bump sets the version of a key to the next sequence, it's
+// called in the same transaction as the value is written.
+func (s *Driver) bump(tx *bolt.Tx, key []byte) error {
+	bucket, err := tx.CreateBucketIfNotExists(s.versionBucket())
+	if err != nil {
+		return err
+	}
+
+	seq, err := bucket.NextSequence()
+	if err != nil {
+		return err
+	}
+
+	value := make([]byte, 8)
+	binary.BigEndian.PutUint64(value, seq)
+
+	return bucket.Put(key, value)
+}
+
+// unversion removes the version of a deleted key.
+func (s *Driver) unversion(tx *bolt.Tx, key []byte) error {
+	bucket := tx.Bucket(s.versionBucket())
+	if bucket == nil {
+		return nil
+	}
+
+	return bucket.Delete(key)
+}
+
+// clearVersions removes the versions of all keys but keeps
+// the sequence, so no version is used again after a flush.
+func (s *Driver) clearVersions(tx *bolt.Tx) error {
+	bucket := tx.Bucket(s.versionBucket())
+	if bucket == nil {
+		return nil
+	}
+
+	seq := bucket.Sequence()
+
+	if err := tx.DeleteBucket(s.versionBucket()); err != nil {
+		return err
+	}
+
+	bucket, err := tx.CreateBucket(s.versionBucket())
+	if err != nil {
+		return err
+	}
+
+	return bucket.SetSequence(seq)
+}
+
+// GetWithVersion returns the value and version for a key.
+func (s *Driver) GetWithVersion(key string, args ...interface{}) (value interface{}, version string, err error) {
+	if err := driver.CheckArgs(args); err != nil {
+		return nil, "", wrapError("get", key, err)
+	}
+
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	db, err := s.db()
+
+	if err != nil {
+		return nil, "", wrapError("get", key, err)
+	}
+
+	err = db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(s.bucket))
+		if bucket == nil {
+			return driver.ErrNotFound
+		}
+
+		res := bucket.Get([]byte(key))
+		if res == nil || s.expired(tx, []byte(key), time.Now()) {
+			return driver.ErrNotFound
+		}
+
+		version = s.version(tx, []byte(key))
+		value, err = driver.Decode(s.codec, res, args)
+
+		return err
+	})
+
+	if err != nil {
+		return nil, "", wrapError("get", key, err)
+	}
+
+	return
+}
+
+// CompareAndSet sets key to value if the version of the key is
+// the given version, the version is checked in the write transaction.
+func (s *Driver) CompareAndSet(key, version string, value interface{}) error {
+	data, err := s.codec.Marshal(value)
+
+	if err != nil {
+		return wrapError("cas", key, err)
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	db, err := s.db()
+
+	if err != nil {
+		return wrapError("cas", key, err)
+	}
+
+	return wrapError("cas", key, db.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists([]byte(s.bucket))
+		if err != nil {
+			return err
+		}
+
+		if s.version(tx, []byte(key)) != version {
+			return driver.ErrConflict
+		}
+
+		if err := s.persist(tx, []byte(key)); err != nil {
+			return err
+		}
+
+		if err := s.bump(tx, []byte(key)); err != nil {
+			return err
+		}
+
+		s.publish(tx, driver.EventSet, key)
+
+		return bucket.Put([]byte(key), data)
+	}))
+}
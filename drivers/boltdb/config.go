@@ -0,0 +1,139 @@
+package boltdb
+
+import (
+	"os"
+
+	"github.com/boltdb/bolt"
+	"github.com/frozzare/go-store/codec"
+	"github.com/frozzare/go-store/driver"
+)
+
+// Config is the configuration of a BoltDB store.
+type Config struct {
+	// Path is the path of the database file, /tmp/store-bolt.db by default.
+	Path string
+
+	// Mode is the file mode the database file is created with, 0600 by default.
+	Mode os.FileMode
+
+	// Options is passed to bolt.Open.
+	Options *bolt.Options
+
+	// Bucket is the bucket the keys is stored in, by
+	// default a md5 hash of the path.
+	Bucket string
+
+	// Codec encodes the values, codec.JSON by default.
+	Codec driver.Codec
+}
+
+// Option configures a BoltDB store.
+type Option func(*Config)
+
+// WithPath sets the path of the database file.
+func WithPath(path string) Option {
+	return func(c *Config) {
+		c.Path = path
+	}
+}
+
+// WithMode sets the file mode the database file is created with.
+func WithMode(mode os.FileMode) Option {
+	return func(c *Config) {
+		c.Mode = mode
+	}
+}
+
+// WithOptions sets the options that is passed to bolt.Open.
+func WithOptions(options *bolt.Options) Option {
+	return func(c *Config) {
+		c.Options = options
+	}
+}
+
+// WithBucket sets the bucket the keys is stored in.
+func WithBucket(bucket string) Option {
+	return func(c *Config) {
+		c.Bucket = bucket
+	}
+}
+
+// newConfig returns the config for the Open args. The args is a
+// Config, Options, a driver.Codec or the positional path, mode
+// and *bolt.Options where a nil value keeps the default.
+func newConfig(args []interface{}) (Config, error) {
+	var c Config
+	var i int
+
+	for _, arg := range args {
+		switch v := arg.(type) {
+		case Option:
+			v(&c)
+			continue
+		case Config:
+			c = v
+			continue
+		case *Config:
+			if v != nil {
+				c = *v
+			}
+			continue
+		case driver.Codec:
+			c.Codec = v
+			continue
+		}
+
+		if arg != nil {
+			switch i {
+			case 0:
+				path, ok := arg.(string)
+
+				if !ok {
+					return c, driver.ArgError("boltdb", i, "a string path", arg)
+				}
+
+				c.Path = path
+			case 1:
+				switch v := arg.(type) {
+				case os.FileMode:
+					c.Mode = v
+				case int:
+					c.Mode = os.FileMode(v)
+				default:
+					return c, driver.ArgError("boltdb", i, "a os.FileMode", arg)
+				}
+			case 2:
+				switch v := arg.(type) {
+				case *bolt.Options:
+					c.Options = v
+				case bolt.Options:
+					c.Options = &v
+				default:
+					return c, driver.ArgError("boltdb", i, "a *bolt.Options", arg)
+				}
+			default:
+				return c, driver.ArgError("boltdb", i, "a Option", arg)
+			}
+		}
+
+		i++
+	}
+
+	if c.Path == "" {
+		c.Path = "/tmp/store-bolt.db"
+	}
+
+	if c.Mode == 0 {
+		c.Mode = 0600
+	}
+
+	if c.Options == nil {
+		c.Options = &bolt.Options{}
+	}
+
+	if c.Codec == nil {
+		c.Codec = codec.JSON{}
+	}
+
+	return c, nil
+}
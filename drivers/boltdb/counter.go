@@ -0,0 +1,72 @@
+package boltdb
+
+import (
+	"time"
+
+	"github.com/boltdb/bolt"
+	"github.com/frozzare/go-store/driver"
+)
+
+// modify replaces the data of a key with the data returned by fn in
+// a single write transaction. The data passed to fn is nil if the key
+// does not exist. The ttl of the key is kept.
+func (s *Driver) modify(op, key string, fn func(data []byte) ([]byte, error)) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	db, err := s.db()
+
+	if err != nil {
+		return wrapError(op, key, err)
+	}
+
+	return wrapError(op, key, db.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists([]byte(s.bucket))
+		if err != nil {
+			return err
+		}
+
+		data := bucket.Get([]byte(key))
+
+		if data != nil && s.expired(tx, []byte(key), time.Now()) {
+			data = nil
+
+			if err := s.persist(tx, []byte(key)); err != nil {
+				return err
+			}
+		}
+
+		data, err = fn(data)
+		if err != nil {
+			return err
+		}
+
+		s.publish(tx, driver.EventSet, key)
+
+		if err := s.bump(tx, []byte(key)); err != nil {
+			return err
+		}
+
+		return bucket.Put([]byte(key), data)
+	}))
+}
+
+// IncrBy adds delta to the integer value of key and returns the new value.
+func (s *Driver) IncrBy(key string, delta int64) (n int64, err error) {
+	err = s.modify("incr", key, func(data []byte) (res []byte, err error) {
+		n, res, err = driver.Add(s.codec, data, delta)
+		return
+	})
+
+	return
+}
+
+// IncrByFloat adds delta to the float value of key and returns the new value.
+func (s *Driver) IncrByFloat(key string, delta float64) (n float64, err error) {
+	err = s.modify("incr", key, func(data []byte) (res []byte, err error) {
+		n, res, err = driver.Add(s.codec, data, delta)
+		return
+	})
+
+	return
+}
@@ -0,0 +1,60 @@
+package boltdb
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/boltdb/bolt"
+	"github.com/frozzare/go-store/driver"
+)
+
+// match returns the keys that matches the pattern. The cursor
+// seeks to the literal prefix of the pattern and the keys with
+// the prefix is matched against the pattern.
+func (s *Driver) match(op, pattern string) (keys []string, err error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	db, err := s.db()
+
+	if err != nil {
+		return nil, wrapError(op, "", err)
+	}
+
+	err = db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(s.bucket))
+		if bucket == nil {
+			return nil
+		}
+
+		c := bucket.Cursor()
+		prefix := []byte(driver.MatchPrefix(pattern))
+		now := time.Now()
+
+		for k, _ := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Next() {
+			if driver.Match(pattern, string(k)) && !s.expired(tx, k, now) {
+				keys = append(keys, string(k))
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, wrapError(op, "", err)
+	}
+
+	return
+}
+
+// KeysMatch returns the keys that matches the pattern.
+func (s *Driver) KeysMatch(pattern string) ([]string, error) {
+	return s.match("keys", pattern)
+}
+
+// CountMatch returns the number of keys that matches the pattern.
+func (s *Driver) CountMatch(pattern string) (int64, error) {
+	keys, err := s.match("count", pattern)
+
+	return int64(len(keys)), err
+}
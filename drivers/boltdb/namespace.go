@@ -0,0 +1,58 @@
+package boltdb
+
+import (
+	"strings"
+
+	"github.com/frozzare/go-store/driver"
+)
+
+// Namespace returns a driver that stores the keys in its own bucket
+// in the same database file. The bucket is named after the bucket of
+// this driver and the name, so namespaces can be nested without
+// seeing each others keys. The name can't contain the "/" separator,
+// so a nested namespace is never the same bucket as a namespace with
+// a longer name. The file is shared and only closed by
+// the driver that opened it, which also stops the janitors of its
+// namespaces.
+func (s *Driver) Namespace(name string) (driver.Driver, error) {
+	if name == "" || strings.Contains(name, "/") {
+		return nil, wrapError("namespace", "", driver.ErrInvalidArgs)
+	}
+
+	root := s
+	if s.root != nil {
+		root = s.root
+	}
+
+	ns := &Driver{
+		config: s.config,
+		bucket: s.bucket + "/" + name,
+		codec:  s.codec,
+		lock:   s.lock,
+		root:   root,
+	}
+
+	ns.janitor = root.namespaceJanitor(ns)
+
+	return ns, nil
+}
+
+// namespaceJanitor returns the janitor for the bucket of the namespace,
+// namespaces with the same bucket shares the janitor.
+func (s *Driver) namespaceJanitor(ns *Driver) *driver.Janitor {
+	s.open.Lock()
+	defer s.open.Unlock()
+
+	if j, ok := s.janitors[ns.bucket]; ok {
+		return j
+	}
+
+	if s.janitors == nil {
+		s.janitors = make(map[string]*driver.Janitor)
+	}
+
+	j := &driver.Janitor{Purge: ns.purge}
+	s.janitors[ns.bucket] = j
+
+	return j
+}
@@ -0,0 +1,81 @@
+package boltdb
+
+import (
+	"time"
+
+	"github.com/boltdb/bolt"
+	"github.com/frozzare/go-store/driver"
+)
+
+// Range returns up to limit keys and values where start <= key < end
+// using a BoltDB cursor.
+func (s *Driver) Range(start, end string, limit int, reverse bool) (pairs []driver.KV, err error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	db, err := s.db()
+
+	if err != nil {
+		return nil, wrapError("range", "", err)
+	}
+
+	err = db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(s.bucket))
+		if bucket == nil {
+			return nil
+		}
+
+		c := bucket.Cursor()
+		now := time.Now()
+
+		var k, v []byte
+		var next func() ([]byte, []byte)
+
+		if reverse {
+			if end == "" {
+				k, v = c.Last()
+			} else if k, v = c.Seek([]byte(end)); k == nil {
+				k, v = c.Last()
+			} else {
+				k, v = c.Prev()
+			}
+
+			next = c.Prev
+		} else {
+			k, v = c.Seek([]byte(start))
+			next = c.Next
+		}
+
+		for ; k != nil; k, v = next() {
+			key := string(k)
+
+			if key < start || (end != "" && key >= end) {
+				break
+			}
+
+			if s.expired(tx, k, now) {
+				continue
+			}
+
+			value, err := driver.Decode(s.codec, v, nil)
+
+			if err != nil {
+				return err
+			}
+
+			pairs = append(pairs, driver.KV{Key: key, Value: value})
+
+			if limit > 0 && len(pairs) == limit {
+				break
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, wrapError("range", "", err)
+	}
+
+	return
+}
@@ -0,0 +1,60 @@
+package boltdb
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/boltdb/bolt"
+)
+
+// Scan returns up to limit keys with the prefix starting at the cursor.
+// The keys is sorted, so the cursor is the last key that was returned.
+func (s *Driver) Scan(prefix, cursor string, limit int) (keys []string, next string, err error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	db, err := s.db()
+
+	if err != nil {
+		return nil, "", wrapError("scan", "", err)
+	}
+
+	err = db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(s.bucket))
+		if bucket == nil {
+			return nil
+		}
+
+		c := bucket.Cursor()
+		p := []byte(prefix)
+		now := time.Now()
+
+		start := p
+		if cursor > prefix {
+			start = []byte(cursor)
+		}
+
+		for k, _ := c.Seek(start); k != nil && bytes.HasPrefix(k, p); k, _ = c.Next() {
+			if string(k) == cursor || s.expired(tx, k, now) {
+				continue
+			}
+
+			// One more key than the limit is read to know
+			// if there is more keys left.
+			if limit > 0 && len(keys) == limit {
+				next = keys[limit-1]
+				break
+			}
+
+			keys = append(keys, string(k))
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, "", wrapError("scan", "", err)
+	}
+
+	return
+}
@@ -0,0 +1,67 @@
+package boltdb
+
+import (
+	"github.com/boltdb/bolt"
+	"github.com/frozzare/go-store/driver"
+)
+
+// setIf sets key to value if the existence of the key
+// is the same as present in a single write transaction.
+func (s *Driver) setIf(key string, value interface{}, present bool) (bool, error) {
+	data, err := s.codec.Marshal(value)
+
+	if err != nil {
+		return false, wrapError("set", key, err)
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	db, err := s.db()
+
+	if err != nil {
+		return false, wrapError("set", key, err)
+	}
+
+	ok := false
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		if s.exists(tx, []byte(key)) != present {
+			return nil
+		}
+
+		bucket, err := tx.CreateBucketIfNotExists([]byte(s.bucket))
+		if err != nil {
+			return err
+		}
+
+		if err := bucket.Put([]byte(key), data); err != nil {
+			return err
+		}
+
+		if err := s.bump(tx, []byte(key)); err != nil {
+			return err
+		}
+
+		ok = true
+		s.publish(tx, driver.EventSet, key)
+
+		return s.persist(tx, []byte(key))
+	})
+
+	if err != nil {
+		return false, wrapError("set", key, err)
+	}
+
+	return ok, nil
+}
+
+// SetIfAbsent sets key to value if the key does not exist.
+func (s *Driver) SetIfAbsent(key string, value interface{}) (bool, error) {
+	return s.setIf(key, value, false)
+}
+
+// SetIfPresent sets key to value if the key exist.
+func (s *Driver) SetIfPresent(key string, value interface{}) (bool, error) {
+	return s.setIf(key, value, true)
+}
@@ -0,0 +1,282 @@
+package boltdb
+
+import (
+	"encoding/binary"
+	"time"
+
+	"github.com/boltdb/bolt"
+	"github.com/frozzare/go-store/driver"
+)
+
+// ttlBucket returns the name of the bucket that holds
+// the expiration time for keys with a ttl.
+func (s *Driver) ttlBucket() []byte {
+	return []byte(s.bucket + ":ttl")
+}
+
+// expires returns the expiration time for a key if any.
+func (s *Driver) expires(tx *bolt.Tx, key []byte) (time.Time, bool) {
+	bucket := tx.Bucket(s.ttlBucket())
+	if bucket == nil {
+		return time.Time{}, false
+	}
+
+	value := bucket.Get(key)
+	if value == nil {
+		return time.Time{}, false
+	}
+
+	return time.Unix(0, int64(binary.BigEndian.Uint64(value))), true
+}
+
+// expired reports if the key has expired.
+func (s *Driver) expired(tx *bolt.Tx, key []byte, now time.Time) bool {
+	expires, ok := s.expires(tx, key)
+
+	return ok && !now.Before(expires)
+}
+
+// countExpired returns the number of expired keys that
+// has not been removed yet.
+func (s *Driver) countExpired(tx *bolt.Tx, now time.Time) (count int64) {
+	bucket := tx.Bucket(s.ttlBucket())
+	if bucket == nil {
+		return
+	}
+
+	bucket.ForEach(func(key []byte, value []byte) error {
+		if s.expired(tx, key, now) {
+			count++
+		}
+
+		return nil
+	})
+
+	return
+}
+
+// expire stores the expiration time for a key.
+func (s *Driver) expire(tx *bolt.Tx, key []byte, ttl time.Duration) error {
+	bucket, err := tx.CreateBucketIfNotExists(s.ttlBucket())
+	if err != nil {
+		return err
+	}
+
+	value := make([]byte, 8)
+	binary.BigEndian.PutUint64(value, uint64(time.Now().Add(ttl).UnixNano()))
+
+	return bucket.Put(key, value)
+}
+
+// persist removes the expiration time for a key.
+func (s *Driver) persist(tx *bolt.Tx, key []byte) error {
+	bucket := tx.Bucket(s.ttlBucket())
+	if bucket == nil {
+		return nil
+	}
+
+	return bucket.Delete(key)
+}
+
+// exists reports if a key exists and has not expired.
+func (s *Driver) exists(tx *bolt.Tx, key []byte) bool {
+	bucket := tx.Bucket([]byte(s.bucket))
+
+	return bucket != nil && bucket.Get(key) != nil && !s.expired(tx, key, time.Now())
+}
+
+// purge removes expired keys and reports if there is keys left with
+// a ttl. Nothing is left once the database is closed, so the janitor
+// stops if the database can't be opened.
+func (s *Driver) purge() bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	db, err := s.db()
+
+	if err != nil {
+		return false
+	}
+
+	left := false
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		ttl := tx.Bucket(s.ttlBucket())
+		if ttl == nil {
+			return nil
+		}
+
+		var keys [][]byte
+		now := time.Now()
+
+		ttl.ForEach(func(key []byte, value []byte) error {
+			if s.expired(tx, key, now) {
+				keys = append(keys, append([]byte{}, key...))
+			} else {
+				left = true
+			}
+
+			return nil
+		})
+
+		bucket := tx.Bucket([]byte(s.bucket))
+
+		for _, key := range keys {
+			s.publish(tx, driver.EventExpire, string(key))
+
+			if bucket != nil {
+				if err := bucket.Delete(key); err != nil {
+					return err
+				}
+			}
+
+			if err := ttl.Delete(key); err != nil {
+				return err
+			}
+
+			if err := s.unversion(tx, key); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	return err != nil || left
+}
+
+// SetWithTTL key with value in store that expires after the ttl.
+func (s *Driver) SetWithTTL(key string, value interface{}, ttl time.Duration) error {
+	if ttl <= 0 {
+		return wrapError("set", key, driver.ErrInvalidArgs)
+	}
+
+	data, err := s.codec.Marshal(value)
+
+	if err != nil {
+		return wrapError("set", key, err)
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	db, err := s.db()
+
+	if err != nil {
+		return wrapError("set", key, err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		s.publish(tx, driver.EventSet, key)
+
+		bucket, err := tx.CreateBucketIfNotExists([]byte(s.bucket))
+		if err != nil {
+			return err
+		}
+
+		if err := bucket.Put([]byte(key), data); err != nil {
+			return err
+		}
+
+		if err := s.bump(tx, []byte(key)); err != nil {
+			return err
+		}
+
+		return s.expire(tx, []byte(key), ttl)
+	})
+
+	if err != nil {
+		return wrapError("set", key, err)
+	}
+
+	s.janitor.Start()
+
+	return nil
+}
+
+// TTL returns the time left before the key expires
+// or NoExpiration if the key does not expire.
+func (s *Driver) TTL(key string) (ttl time.Duration, err error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	db, err := s.db()
+
+	if err != nil {
+		return 0, wrapError("ttl", key, err)
+	}
+
+	err = db.View(func(tx *bolt.Tx) error {
+		if !s.exists(tx, []byte(key)) {
+			return driver.ErrNotFound
+		}
+
+		expires, ok := s.expires(tx, []byte(key))
+
+		if ok {
+			ttl = time.Until(expires)
+		} else {
+			ttl = driver.NoExpiration
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return 0, wrapError("ttl", key, err)
+	}
+
+	return
+}
+
+// Expire sets the ttl for a existing key.
+func (s *Driver) Expire(key string, ttl time.Duration) error {
+	if ttl <= 0 {
+		return wrapError("expire", key, driver.ErrInvalidArgs)
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	db, err := s.db()
+
+	if err != nil {
+		return wrapError("expire", key, err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		if !s.exists(tx, []byte(key)) {
+			return driver.ErrNotFound
+		}
+
+		return s.expire(tx, []byte(key), ttl)
+	})
+
+	if err != nil {
+		return wrapError("expire", key, err)
+	}
+
+	s.janitor.Start()
+
+	return nil
+}
+
+// Persist removes the expiration from a existing key.
+func (s *Driver) Persist(key string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	db, err := s.db()
+
+	if err != nil {
+		return wrapError("persist", key, err)
+	}
+
+	return wrapError("persist", key, db.Update(func(tx *bolt.Tx) error {
+		if !s.exists(tx, []byte(key)) {
+			return driver.ErrNotFound
+		}
+
+		return s.persist(tx, []byte(key))
+	}))
+}
@@ -0,0 +1,107 @@
+package boltdb
+
+import (
+	"time"
+
+	"github.com/boltdb/bolt"
+	"github.com/frozzare/go-store/driver"
+)
+
+// tx represents a BoltDB transaction.
+type tx struct {
+	s  *Driver
+	tx *bolt.Tx
+}
+
+// Exists returns true when a key exists false when not existing in store.
+func (t *tx) Exists(key string) (bool, error) {
+	return t.s.exists(t.tx, []byte(key)), nil
+}
+
+// Get returns the value for a key if any.
+func (t *tx) Get(key string, args ...interface{}) (interface{}, error) {
+	if err := driver.CheckArgs(args); err != nil {
+		return nil, wrapError("get", key, err)
+	}
+
+	bucket := t.tx.Bucket([]byte(t.s.bucket))
+	if bucket == nil {
+		return nil, wrapError("get", key, driver.ErrNotFound)
+	}
+
+	res := bucket.Get([]byte(key))
+	if res == nil || t.s.expired(t.tx, []byte(key), time.Now()) {
+		return nil, wrapError("get", key, driver.ErrNotFound)
+	}
+
+	value, err := driver.Decode(t.s.codec, res, args)
+
+	if err != nil {
+		return nil, wrapError("get", key, err)
+	}
+
+	return value, nil
+}
+
+// Set key with value in store.
+func (t *tx) Set(key string, value interface{}) error {
+	data, err := t.s.codec.Marshal(value)
+
+	if err != nil {
+		return wrapError("set", key, err)
+	}
+
+	bucket, err := t.tx.CreateBucketIfNotExists([]byte(t.s.bucket))
+	if err != nil {
+		return wrapError("set", key, err)
+	}
+
+	if err := t.s.persist(t.tx, []byte(key)); err != nil {
+		return wrapError("set", key, err)
+	}
+
+	if err := t.s.bump(t.tx, []byte(key)); err != nil {
+		return wrapError("set", key, err)
+	}
+
+	t.s.publish(t.tx, driver.EventSet, key)
+
+	return wrapError("set", key, bucket.Put([]byte(key), data))
+}
+
+// Delete key from store.
+func (t *tx) Delete(key string) error {
+	bucket, err := t.tx.CreateBucketIfNotExists([]byte(t.s.bucket))
+	if err != nil {
+		return wrapError("delete", key, err)
+	}
+
+	if err := bucket.Delete([]byte(key)); err != nil {
+		return wrapError("delete", key, err)
+	}
+
+	if err := t.s.unversion(t.tx, []byte(key)); err != nil {
+		return wrapError("delete", key, err)
+	}
+
+	t.s.publish(t.tx, driver.EventDelete, key)
+
+	return wrapError("delete", key, t.s.persist(t.tx, []byte(key)))
+}
+
+// Update runs fn in a BoltDB write transaction. The changes is
+// committed if fn returns nil and rolled back if not.
+func (s *Driver) Update(fn func(tx driver.Tx) error) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	db, err := s.db()
+
+	if err != nil {
+		return wrapError("update", "", err)
+	}
+
+	return db.Update(func(btx *bolt.Tx) error {
+		return fn(&tx{s: s, tx: btx})
+	})
+}
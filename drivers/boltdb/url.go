@@ -0,0 +1,59 @@
+package boltdb
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+
+	"github.com/boltdb/bolt"
+	"github.com/frozzare/go-store/driver"
+)
+
+// init registers the bolt:// url scheme.
+func init() {
+	driver.RegisterURL("bolt", openURL)
+}
+
+// openURL opens a store from a bolt:///path/to/file.db url. The query
+// parameters is bucket, codec, mode (octal), read_only and timeout.
+func openURL(u *url.URL) (driver.Driver, error) {
+	if err := driver.URLParams(u, "bucket", "codec", "mode", "read_only", "timeout"); err != nil {
+		return nil, err
+	}
+
+	codec, err := driver.URLCodec(u)
+
+	if err != nil {
+		return nil, err
+	}
+
+	options := &bolt.Options{}
+
+	if options.Timeout, err = driver.URLDuration(u, "timeout"); err != nil {
+		return nil, err
+	}
+
+	if options.ReadOnly, err = driver.URLBool(u, "read_only"); err != nil {
+		return nil, err
+	}
+
+	opts := []interface{}{
+		codec,
+		WithOptions(options),
+		WithBucket(u.Query().Get("bucket")),
+		WithPath(driver.URLPath(u)),
+	}
+
+	if value := u.Query().Get("mode"); value != "" {
+		mode, err := strconv.ParseUint(value, 8, 32)
+
+		if err != nil {
+			return nil, fmt.Errorf("store: invalid bolt url parameter mode=%q: %w", value, driver.ErrInvalidArgs)
+		}
+
+		opts = append(opts, WithMode(os.FileMode(mode)))
+	}
+
+	return Open(opts...)
+}
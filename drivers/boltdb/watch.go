@@ -0,0 +1,24 @@
+package boltdb
+
+import (
+	"context"
+
+	"github.com/boltdb/bolt"
+	"github.com/frozzare/go-store/driver"
+)
+
+// publish sends the events to the watchers when the transaction
+// is committed, nothing is sent if it's rolled back.
+func (s *Driver) publish(tx *bolt.Tx, typ driver.EventType, keys ...string) {
+	tx.OnCommit(func() {
+		s.hub.Publish(typ, keys...)
+	})
+}
+
+// Watch returns a channel that receives events for keys with the
+// prefix until the context is done. Only the changes made through
+// this driver is sent, and Expire events is sent when the janitor
+// removes the expired keys.
+func (s *Driver) Watch(ctx context.Context, prefix string) (<-chan driver.Event, error) {
+	return s.hub.Watch(ctx, prefix), nil
+}
@@ -0,0 +1,128 @@
+package buntdb
+
+import (
+	"github.com/frozzare/go-store/driver"
+	bunt "github.com/tidwall/buntdb"
+)
+
+// GetMulti returns the values for the keys that exists in store.
+func (s *Driver) GetMulti(keys []string, args ...interface{}) (values map[string]interface{}, err error) {
+	if err := driver.CheckArgs(args); err != nil {
+		return nil, wrapError("getmulti", "", err)
+	}
+
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	db, err := s.db()
+
+	if err != nil {
+		return nil, wrapError("getmulti", "", err)
+	}
+
+	err = db.View(func(tx *bunt.Tx) error {
+		values, err = driver.DecodeMulti(keys, args, func(key string, v interface{}) error {
+			val, err := tx.Get(key)
+
+			if err == bunt.ErrNotFound {
+				return driver.ErrNotFound
+			}
+
+			if err != nil {
+				return err
+			}
+
+			return driver.Unmarshal(s.codec, []byte(val), v)
+		})
+
+		return err
+	})
+
+	if err != nil {
+		return nil, wrapError("getmulti", "", err)
+	}
+
+	return
+}
+
+// SetMulti keys with values in store.
+func (s *Driver) SetMulti(values map[string]interface{}) error {
+	data := make(map[string]string, len(values))
+	keys := make([]string, 0, len(values))
+
+	for key, value := range values {
+		b, err := s.codec.Marshal(value)
+
+		if err != nil {
+			return wrapError("setmulti", key, err)
+		}
+
+		data[key] = string(b)
+		keys = append(keys, key)
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	db, err := s.db()
+
+	if err != nil {
+		return wrapError("setmulti", "", err)
+	}
+
+	err = db.Update(func(tx *bunt.Tx) error {
+		for key, value := range data {
+			if _, _, err := tx.Set(key, value, nil); err != nil {
+				return err
+			}
+
+			if err := bump(tx, key); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return wrapError("setmulti", "", err)
+	}
+
+	s.hub.Publish(driver.EventSet, keys...)
+
+	return nil
+}
+
+// DeleteMulti keys from store.
+func (s *Driver) DeleteMulti(keys ...string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	db, err := s.db()
+
+	if err != nil {
+		return wrapError("deletemulti", "", err)
+	}
+
+	err = db.Update(func(tx *bunt.Tx) error {
+		for _, key := range keys {
+			if _, err := tx.Delete(key); err != nil && err != bunt.ErrNotFound {
+				return err
+			}
+
+			if err := unversion(tx, key); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return wrapError("deletemulti", "", err)
+	}
+
+	s.hub.Publish(driver.EventDelete, keys...)
+
+	return nil
+}
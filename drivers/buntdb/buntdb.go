@@ -1,8 +1,8 @@
 package buntdb
 
 import (
-	"encoding/json"
-	"reflect"
+	"context"
+	"sync"
 
 	bunt "github.com/tidwall/buntdb"
 
@@ -11,29 +11,52 @@ import (
 
 // Driver represents a BundDB driver.
 type Driver struct {
-	args   []interface{}
+	config Config
 	closed bool
 	client *bunt.DB
+	codec  driver.Codec
+	hub    driver.Hub
+
+	// lock is read locked by the calls that reads and write locked
+	// by the calls that writes, so Close waits for the calls in flight.
+	lock sync.RWMutex
+
+	// open serializes the calls that opens the client.
+	open sync.Mutex
 }
 
 // db returns the BundDB client if existing
-// or creating a new if closed.
+// or opening it on the first call, ErrClosed is
+// returned after Close.
 func (s *Driver) db() (*bunt.DB, error) {
-	if s.client != nil && !s.closed {
+	s.open.Lock()
+	defer s.open.Unlock()
+
+	if s.closed {
+		return nil, driver.ErrClosed
+	}
+
+	if s.client != nil {
 		return s.client, nil
 	}
 
-	s.closed = false
+	db, err := bunt.Open(s.config.Path)
+
+	if err != nil {
+		return nil, err
+	}
 
-	path := "/tmp/store-bunt.db"
+	var config bunt.Config
 
-	if len(s.args) > 0 && s.args[0] != nil {
-		path = s.args[0].(string)
+	if err := db.ReadConfig(&config); err != nil {
+		db.Close()
+		return nil, err
 	}
 
-	db, err := bunt.Open(path)
+	config.OnExpiredSync = s.onExpired
 
-	if err != nil {
+	if err := db.SetConfig(config); err != nil {
+		db.Close()
 		return nil, err
 	}
 
@@ -42,9 +65,21 @@ func (s *Driver) db() (*bunt.DB, error) {
 	return s.client, nil
 }
 
-// Open creates a new BundDB store.
+// init registers the driver with the name buntdb.
+func init() {
+	driver.RegisterFactory("buntdb", Open)
+}
+
+// Open creates a new BundDB store. The args is a Config, Options
+// such as WithPath, a driver.Codec or the positional path.
 func Open(args ...interface{}) (driver.Driver, error) {
-	return &Driver{args: args}, nil
+	config, err := newConfig(args)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Driver{config: config, codec: config.Codec}, nil
 }
 
 // Open creates a new BundDB store with a specified instance.
@@ -52,85 +87,177 @@ func (s *Driver) Open(args ...interface{}) (driver.Driver, error) {
 	return Open(args...)
 }
 
+// Capabilities returns the optional features the driver supports.
+func (s *Driver) Capabilities() driver.Capabilities {
+	return driver.CapContext | driver.CapTTL | driver.CapBatch | driver.CapTx |
+		driver.CapScan | driver.CapMatch | driver.CapRange | driver.CapCAS |
+		driver.CapCounter | driver.CapConditional | driver.CapWatch
+}
+
+// wrapError maps BuntDB errors onto the driver errors
+// and records the operation and key.
+func wrapError(op, key string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	switch err {
+	case bunt.ErrNotFound:
+		err = driver.ErrNotFound
+	case bunt.ErrDatabaseClosed:
+		err = driver.ErrClosed
+	}
+
+	return &driver.Error{Op: op, Key: key, Driver: "buntdb", Err: err}
+}
+
 // Count returns numbers of keys in store.
-func (s *Driver) Count() (count int64, err error) {
-	defer s.Close()
+func (s *Driver) Count() (int64, error) {
+	return s.CountContext(context.Background())
+}
+
+// CountContext returns numbers of keys in store.
+func (s *Driver) CountContext(ctx context.Context) (count int64, err error) {
+	if err := ctx.Err(); err != nil {
+		return 0, wrapError("count", "", err)
+	}
+
+	s.lock.RLock()
+	defer s.lock.RUnlock()
 
 	db, err := s.db()
 
 	if err != nil {
-		return
+		return 0, wrapError("count", "", err)
 	}
 
 	err = db.View(func(tx *bunt.Tx) error {
 		return tx.Ascend("", func(key, value string) bool {
-			count++
+			if !internal(key) && !expired(tx, key) {
+				count++
+			}
 
-			return true
+			return ctx.Err() == nil
 		})
 	})
 
+	if err == nil {
+		err = ctx.Err()
+	}
+
+	if err != nil {
+		return 0, wrapError("count", "", err)
+	}
+
 	return
 }
 
 // Exists returns true when a key exists false when not existing in store.
-func (s *Driver) Exists(key string) (exists bool, err error) {
-	defer s.Close()
+func (s *Driver) Exists(key string) (bool, error) {
+	return s.ExistsContext(context.Background(), key)
+}
+
+// ExistsContext returns true when a key exists false when not existing in store.
+func (s *Driver) ExistsContext(ctx context.Context, key string) (exists bool, err error) {
+	if err := ctx.Err(); err != nil {
+		return false, wrapError("exists", key, err)
+	}
+
+	s.lock.RLock()
+	defer s.lock.RUnlock()
 
 	db, err := s.db()
 
 	if err != nil {
-		return
+		return false, wrapError("exists", key, err)
 	}
 
 	err = db.View(func(tx *bunt.Tx) error {
-		v, err := tx.Get(key)
+		_, err := tx.Get(key)
+
+		if err == bunt.ErrNotFound {
+			return nil
+		}
 
 		if err != nil {
 			return err
 		}
 
-		exists = len(v) > 0
+		exists = true
 
 		return nil
 	})
 
+	if err != nil {
+		return false, wrapError("exists", key, err)
+	}
+
 	return
 }
 
 // Keys returns a string slice with all keys.
-func (s *Driver) Keys() (keys []string, err error) {
-	defer s.Close()
+func (s *Driver) Keys() ([]string, error) {
+	return s.KeysContext(context.Background())
+}
+
+// KeysContext returns a string slice with all keys.
+func (s *Driver) KeysContext(ctx context.Context) (keys []string, err error) {
+	if err := ctx.Err(); err != nil {
+		return []string{}, wrapError("keys", "", err)
+	}
+
+	s.lock.RLock()
+	defer s.lock.RUnlock()
 
 	db, err := s.db()
 
 	if err != nil {
-		return []string{}, err
+		return []string{}, wrapError("keys", "", err)
 	}
 
 	err = db.View(func(tx *bunt.Tx) error {
 		return tx.Ascend("", func(key, value string) bool {
-			keys = append(keys, key)
+			if !internal(key) && !expired(tx, key) {
+				keys = append(keys, key)
+			}
 
-			return true
+			return ctx.Err() == nil
 		})
 	})
 
+	if err == nil {
+		err = ctx.Err()
+	}
+
 	if err != nil {
-		return []string{}, err
+		return []string{}, wrapError("keys", "", err)
 	}
 
 	return keys, nil
 }
 
 // Get returns the value for a key if any.
-func (s *Driver) Get(key string, args ...interface{}) (value interface{}, err error) {
-	defer s.Close()
+func (s *Driver) Get(key string, args ...interface{}) (interface{}, error) {
+	return s.GetContext(context.Background(), key, args...)
+}
+
+// GetContext returns the value for a key if any.
+func (s *Driver) GetContext(ctx context.Context, key string, args ...interface{}) (value interface{}, err error) {
+	if err := ctx.Err(); err != nil {
+		return nil, wrapError("get", key, err)
+	}
+
+	if err := driver.CheckArgs(args); err != nil {
+		return nil, wrapError("get", key, err)
+	}
+
+	s.lock.RLock()
+	defer s.lock.RUnlock()
 
 	db, err := s.db()
 
 	if err != nil {
-		return nil, err
+		return nil, wrapError("get", key, err)
 	}
 
 	err = db.View(func(tx *bunt.Tx) error {
@@ -140,97 +267,172 @@ func (s *Driver) Get(key string, args ...interface{}) (value interface{}, err er
 			return err
 		}
 
-		if len(args) > 0 {
-			value = args[0]
-		}
-
-		if err = json.Unmarshal([]byte(val), &value); err == nil {
-			return nil
-		}
+		value, err = driver.Decode(s.codec, []byte(val), args)
 
-		value = val
-
-		return nil
+		return err
 	})
 
+	if err != nil {
+		return nil, wrapError("get", key, err)
+	}
+
 	return
 }
 
 // Set key with value in store.
 func (s *Driver) Set(key string, value interface{}) error {
-	defer s.Close()
+	return s.SetContext(context.Background(), key, value)
+}
 
-	db, err := s.db()
+// SetContext key with value in store.
+func (s *Driver) SetContext(ctx context.Context, key string, value interface{}) error {
+	if err := ctx.Err(); err != nil {
+		return wrapError("set", key, err)
+	}
+
+	data, err := s.codec.Marshal(value)
 
 	if err != nil {
-		return err
+		return wrapError("set", key, err)
 	}
 
-	return db.Update(func(tx *bunt.Tx) error {
-		if reflect.TypeOf(value).Kind() != reflect.String {
-			value, err := json.Marshal(value)
+	s.lock.Lock()
+	defer s.lock.Unlock()
 
-			if err != nil {
-				return err
-			}
+	db, err := s.db()
 
-			tx.Set(key, string(value), nil)
-		} else {
+	if err != nil {
+		return wrapError("set", key, err)
+	}
 
-			tx.Set(key, value.(string), nil)
+	err = db.Update(func(tx *bunt.Tx) error {
+		if _, _, err := tx.Set(key, string(data), nil); err != nil {
+			return err
 		}
 
-		return nil
+		return bump(tx, key)
 	})
+
+	if err != nil {
+		return wrapError("set", key, err)
+	}
+
+	s.hub.Publish(driver.EventSet, key)
+
+	return nil
 }
 
 // Delete key from store.
 func (s *Driver) Delete(key string) error {
-	defer s.Close()
+	return s.DeleteContext(context.Background(), key)
+}
+
+// DeleteContext key from store.
+func (s *Driver) DeleteContext(ctx context.Context, key string) error {
+	if err := ctx.Err(); err != nil {
+		return wrapError("delete", key, err)
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
 
 	db, err := s.db()
 
 	if err != nil {
-		return err
+		return wrapError("delete", key, err)
 	}
 
-	return db.Update(func(tx *bunt.Tx) error {
-		_, err := tx.Delete(key)
+	err = db.Update(func(tx *bunt.Tx) error {
+		if _, err := tx.Delete(key); err != nil {
+			return err
+		}
 
-		return err
+		return unversion(tx, key)
 	})
-}
 
-// Close will close the boltdb client.
-func (s *Driver) Close() error {
-	db, err := s.db()
+	if err == bunt.ErrNotFound {
+		return nil
+	}
 
 	if err != nil {
-		return err
+		return wrapError("delete", key, err)
 	}
 
-	err = db.Close()
+	s.hub.Publish(driver.EventDelete, key)
 
-	if err != nil {
-		return err
+	return nil
+}
+
+// Close waits for the calls in flight and closes the database,
+// the calls made after Close returns ErrClosed.
+func (s *Driver) Close() error {
+	err := s.close()
+
+	s.hub.Close()
+
+	return err
+}
+
+// close marks the driver as closed and closes the client.
+func (s *Driver) close() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.closed {
+		return nil
 	}
 
 	s.closed = true
 
-	return nil
+	if s.client == nil {
+		return nil
+	}
+
+	return wrapError("close", "", s.client.Close())
 }
 
 // Flush will remove all keys and values from the store.
 func (s *Driver) Flush() error {
-	defer s.Close()
+	return s.FlushContext(context.Background())
+}
+
+// FlushContext will remove all keys and values from the store.
+func (s *Driver) FlushContext(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return wrapError("flush", "", err)
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
 
 	db, err := s.db()
 
 	if err != nil {
-		return err
+		return wrapError("flush", "", err)
 	}
 
-	return db.Update(func(tx *bunt.Tx) error {
-		return tx.DeleteAll()
+	// The sequence is kept so no version is used again after the flush.
+	err = db.Update(func(tx *bunt.Tx) error {
+		seq, err := tx.Get(sequenceKey)
+
+		if err != nil && err != bunt.ErrNotFound {
+			return err
+		}
+
+		if err := tx.DeleteAll(); err != nil || seq == "" {
+			return err
+		}
+
+		_, _, err = tx.Set(sequenceKey, seq, nil)
+
+		return err
 	})
+
+	if err != nil {
+		return wrapError("flush", "", err)
+	}
+
+	s.hub.Publish(driver.EventFlush)
+
+	return nil
 }
@@ -1,13 +1,23 @@
 package buntdb
 
 import (
+	"context"
+	"errors"
+	"net/url"
+	"sort"
+	"strings"
 	"testing"
+	"time"
 
 	assert "github.com/frozzare/go-assert"
+	"github.com/frozzare/go-store/codec"
+	"github.com/frozzare/go-store/driver"
+	"github.com/frozzare/go-store/storetest"
 )
 
 func TestCustomOptions(t *testing.T) {
 	s, _ := Open("/tmp/custom-buntdb.db")
+	defer s.Close()
 
 	v, _ := s.Get("name")
 	assert.Nil(t, v)
@@ -20,8 +30,26 @@ func TestCustomOptions(t *testing.T) {
 	s.Delete("name")
 }
 
+func TestOptions(t *testing.T) {
+	s, err := Open(WithPath("/tmp/custom-buntdb.db"), codec.Gob{})
+	assert.Nil(t, err)
+	assert.Equal(t, "/tmp/custom-buntdb.db", s.(*Driver).config.Path)
+	assert.Equal(t, codec.Gob{}, s.(*Driver).codec)
+
+	s, err = Open(Config{Path: "/tmp/custom-buntdb.db"})
+	assert.Nil(t, err)
+	assert.Equal(t, "/tmp/custom-buntdb.db", s.(*Driver).config.Path)
+
+	_, err = Open(1)
+	assert.True(t, errors.Is(err, driver.ErrInvalidArgs))
+
+	_, err = Open("/tmp/custom-buntdb.db", "read_only")
+	assert.True(t, errors.Is(err, driver.ErrInvalidArgs))
+}
+
 func TestGetSetSimple(t *testing.T) {
 	s, _ := Open()
+	defer s.Close()
 
 	v, _ := s.Get("name")
 	assert.Nil(t, v)
@@ -36,6 +64,7 @@ func TestGetSetSimple(t *testing.T) {
 
 func TestGetSetMap(t *testing.T) {
 	s, _ := Open()
+	defer s.Close()
 
 	v, _ := s.Get("map")
 	assert.Nil(t, v)
@@ -50,6 +79,7 @@ func TestGetSetMap(t *testing.T) {
 
 func TestCount(t *testing.T) {
 	s, _ := Open()
+	defer s.Close()
 
 	c, _ := s.Count()
 	assert.Equal(t, 0, c)
@@ -63,6 +93,7 @@ func TestCount(t *testing.T) {
 
 func TestExists(t *testing.T) {
 	s, _ := Open()
+	defer s.Close()
 
 	e, _ := s.Exists("name")
 	assert.False(t, e)
@@ -76,6 +107,7 @@ func TestExists(t *testing.T) {
 
 func TestDeleteSimple(t *testing.T) {
 	s, _ := Open()
+	defer s.Close()
 
 	v, _ := s.Get("name")
 	assert.Nil(t, v)
@@ -92,6 +124,7 @@ func TestDeleteSimple(t *testing.T) {
 
 func TestKeys(t *testing.T) {
 	s, _ := Open()
+	defer s.Close()
 
 	k, _ := s.Keys()
 	assert.Equal(t, 0, len(k))
@@ -108,6 +141,7 @@ func TestKeys(t *testing.T) {
 
 func TestFlush(t *testing.T) {
 	s, _ := Open()
+	defer s.Close()
 
 	s.Set("name", "Fredrik")
 
@@ -126,6 +160,7 @@ type Person struct {
 
 func TestGetSetSimpleStruct(t *testing.T) {
 	s, _ := Open()
+	defer s.Close()
 
 	v, _ := s.Get("name")
 	assert.Nil(t, v)
@@ -138,3 +173,548 @@ func TestGetSetSimpleStruct(t *testing.T) {
 
 	s.Delete("name")
 }
+
+func TestGetSetContext(t *testing.T) {
+	d, _ := Open()
+	defer d.Close()
+	s := d.(driver.ContextDriver)
+	ctx := context.Background()
+
+	v, _ := s.GetContext(ctx, "name")
+	assert.Nil(t, v)
+
+	s.SetContext(ctx, "name", "Fredrik")
+
+	v, _ = s.GetContext(ctx, "name")
+	assert.Equal(t, "Fredrik", v.(string))
+
+	s.DeleteContext(ctx, "name")
+}
+
+func TestCanceledContext(t *testing.T) {
+	d, _ := Open()
+	defer d.Close()
+	s := d.(driver.ContextDriver)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	assert.True(t, errors.Is(s.SetContext(ctx, "name", "Fredrik"), context.Canceled))
+
+	e, _ := s.Exists("name")
+	assert.False(t, e)
+
+	_, err := s.KeysContext(ctx)
+	assert.True(t, errors.Is(err, context.Canceled))
+}
+
+func TestNotFound(t *testing.T) {
+	s, _ := Open()
+	defer s.Close()
+
+	v, err := s.Get("missing")
+	assert.Nil(t, v)
+	assert.True(t, errors.Is(err, driver.ErrNotFound))
+
+	e, err := s.Exists("missing")
+	assert.False(t, e)
+	assert.Nil(t, err)
+
+	assert.Nil(t, s.Delete("missing"))
+}
+
+func TestGetSetNumericString(t *testing.T) {
+	s, _ := Open()
+	defer s.Close()
+
+	s.Set("number", "123")
+
+	v, _ := s.Get("number")
+	assert.Equal(t, "123", v.(string))
+
+	s.Delete("number")
+}
+
+func TestCustomCodec(t *testing.T) {
+	s, _ := Open(codec.Gob{})
+	defer s.Close()
+
+	s.Set("name", &Person{Name: "Fredrik"})
+
+	var p *Person
+	_, err := s.Get("name", &p)
+	assert.Nil(t, err)
+	assert.Equal(t, "Fredrik", p.Name)
+
+	s.Delete("name")
+}
+
+func TestEnvelopeTypes(t *testing.T) {
+	s, _ := Open(codec.Envelope{})
+	defer s.Close()
+	now := time.Now().UTC()
+
+	s.Set("int", int64(1<<62+1))
+	s.Set("nil", nil)
+	s.Set("bytes", []byte("Fredrik"))
+	s.Set("time", now)
+
+	v, _ := s.Get("int")
+	assert.Equal(t, int64(1<<62+1), v.(int64))
+
+	v, err := s.Get("nil")
+	assert.Nil(t, v)
+	assert.Nil(t, err)
+
+	v, _ = s.Get("bytes")
+	assert.Equal(t, "Fredrik", string(v.([]byte)))
+
+	v, _ = s.Get("time")
+	assert.True(t, now.Equal(v.(time.Time)))
+
+	s.Flush()
+}
+
+func TestTTL(t *testing.T) {
+	d, _ := Open()
+	defer d.Close()
+	s := d.(driver.TTLDriver)
+	d.Flush()
+
+	assert.True(t, errors.Is(s.SetWithTTL("name", "Fredrik", 0), driver.ErrInvalidArgs))
+
+	s.SetWithTTL("name", "Fredrik", time.Minute)
+
+	ttl, err := s.TTL("name")
+	assert.Nil(t, err)
+	assert.True(t, ttl > 0 && ttl <= time.Minute)
+
+	d.Set("name", "Fredrik")
+
+	ttl, _ = s.TTL("name")
+	assert.Equal(t, driver.NoExpiration, ttl)
+
+	s.Expire("name", time.Minute)
+	assert.Nil(t, s.Persist("name"))
+
+	ttl, _ = s.TTL("name")
+	assert.Equal(t, driver.NoExpiration, ttl)
+
+	assert.Nil(t, s.Expire("name", 50*time.Millisecond))
+	time.Sleep(100 * time.Millisecond)
+
+	v, err := d.Get("name")
+	assert.Nil(t, v)
+	assert.True(t, errors.Is(err, driver.ErrNotFound))
+
+	e, _ := d.Exists("name")
+	assert.False(t, e)
+
+	keys, _ := d.Keys()
+	assert.Equal(t, 0, len(keys))
+
+	count, _ := d.Count()
+	assert.Equal(t, int64(0), count)
+
+	_, err = s.TTL("name")
+	assert.True(t, errors.Is(err, driver.ErrNotFound))
+	assert.True(t, errors.Is(s.Expire("name", time.Minute), driver.ErrNotFound))
+	assert.True(t, errors.Is(s.Persist("name"), driver.ErrNotFound))
+
+	d.Flush()
+}
+
+func TestMulti(t *testing.T) {
+	d, _ := Open()
+	defer d.Close()
+	s := d.(driver.BatchDriver)
+
+	assert.Nil(t, s.SetMulti(map[string]interface{}{"a": "1", "b": "2"}))
+
+	values, err := s.GetMulti([]string{"a", "b", "c"})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(values))
+	assert.Equal(t, "1", values["a"].(string))
+
+	var strs map[string]string
+	_, err = s.GetMulti([]string{"a", "b", "c"}, &strs)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(strs))
+	assert.Equal(t, "2", strs["b"])
+
+	assert.Nil(t, s.DeleteMulti("a", "b", "c"))
+
+	values, _ = s.GetMulti([]string{"a", "b"})
+	assert.Equal(t, 0, len(values))
+}
+
+func TestUpdate(t *testing.T) {
+	d, _ := Open()
+	defer d.Close()
+	s := d.(driver.TxDriver)
+
+	d.Set("a", "1")
+	d.Set("b", "2")
+
+	err := s.Update(func(tx driver.Tx) error {
+		v, err := tx.Get("a")
+		if err != nil {
+			return err
+		}
+
+		tx.Set("b", v)
+		tx.Delete("a")
+
+		e, _ := tx.Exists("a")
+		assert.False(t, e)
+
+		return nil
+	})
+	assert.Nil(t, err)
+
+	v, _ := d.Get("b")
+	assert.Equal(t, "1", v.(string))
+
+	e, _ := d.Exists("a")
+	assert.False(t, e)
+
+	rollback := errors.New("rollback")
+
+	err = s.Update(func(tx driver.Tx) error {
+		tx.Set("b", "3")
+		tx.Set("c", "3")
+
+		return rollback
+	})
+	assert.Equal(t, rollback, err)
+
+	v, _ = d.Get("b")
+	assert.Equal(t, "1", v.(string))
+
+	e, _ = d.Exists("c")
+	assert.False(t, e)
+
+	d.Flush()
+}
+
+func TestScan(t *testing.T) {
+	d, _ := Open()
+	defer d.Close()
+	s := d.(driver.ScanDriver)
+	d.Flush()
+
+	for _, key := range []string{"a:1", "a:2", "a:3", "b:1"} {
+		d.Set(key, "Fredrik")
+	}
+
+	var keys []string
+	var cursor string
+
+	for {
+		page, next, err := s.Scan("a:", cursor, 2)
+		assert.Nil(t, err)
+
+		keys = append(keys, page...)
+
+		if next == "" {
+			break
+		}
+
+		cursor = next
+	}
+
+	sort.Strings(keys)
+	assert.Equal(t, "a:1,a:2,a:3", strings.Join(keys, ","))
+
+	keys, cursor, _ = s.Scan("", "", 0)
+	assert.Equal(t, 4, len(keys))
+	assert.Equal(t, "", cursor)
+
+	d.Flush()
+}
+
+func TestKeysCountMatch(t *testing.T) {
+	d, _ := Open()
+	defer d.Close()
+	s := d.(driver.MatchDriver)
+	d.Flush()
+
+	for _, key := range []string{"session:1", "session:2", "user:1:profile", "user:12:profile"} {
+		d.Set(key, "Fredrik")
+	}
+
+	keys, err := s.KeysMatch("session:*")
+	assert.Nil(t, err)
+
+	sort.Strings(keys)
+	assert.Equal(t, "session:1,session:2", strings.Join(keys, ","))
+
+	count, err := s.CountMatch("user:?:profile")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), count)
+
+	count, _ = s.CountMatch("user:[0-9]*:profile")
+	assert.Equal(t, int64(2), count)
+
+	count, _ = s.CountMatch("*")
+	assert.Equal(t, int64(4), count)
+
+	d.Flush()
+}
+
+func TestRange(t *testing.T) {
+	d, _ := Open()
+	defer d.Close()
+	s := d.(driver.RangeDriver)
+	d.Flush()
+
+	for _, key := range []string{"a", "b", "c", "d"} {
+		d.Set(key, key)
+	}
+
+	keys := func(pairs []driver.KV) string {
+		var keys []string
+
+		for _, p := range pairs {
+			keys = append(keys, p.Key)
+		}
+
+		return strings.Join(keys, ",")
+	}
+
+	pairs, err := s.Range("b", "d", 0, false)
+	assert.Nil(t, err)
+	assert.Equal(t, "b,c", keys(pairs))
+	assert.Equal(t, "b", pairs[0].Value.(string))
+
+	pairs, _ = s.Range("b", "d", 0, true)
+	assert.Equal(t, "c,b", keys(pairs))
+
+	pairs, _ = s.Range("", "", 3, false)
+	assert.Equal(t, "a,b,c", keys(pairs))
+
+	pairs, _ = s.Range("", "", 3, true)
+	assert.Equal(t, "d,c,b", keys(pairs))
+
+	pairs, _ = s.Range("b", "", 0, true)
+	assert.Equal(t, "d,c,b", keys(pairs))
+
+	d.Flush()
+}
+
+func TestCompareAndSet(t *testing.T) {
+	d, _ := Open()
+	defer d.Close()
+	s := d.(driver.CASDriver)
+	d.Flush()
+
+	_, _, err := s.GetWithVersion("name")
+	assert.True(t, errors.Is(err, driver.ErrNotFound))
+
+	assert.Nil(t, s.CompareAndSet("name", "", "Fredrik"))
+	assert.True(t, errors.Is(s.CompareAndSet("name", "", "Fredrik"), driver.ErrConflict))
+
+	v, version, err := s.GetWithVersion("name")
+	assert.Nil(t, err)
+	assert.Equal(t, "Fredrik", v.(string))
+
+	d.Set("name", "Elli")
+
+	assert.True(t, errors.Is(s.CompareAndSet("name", version, "Fredrik"), driver.ErrConflict))
+
+	_, version, _ = s.GetWithVersion("name")
+	assert.Nil(t, s.CompareAndSet("name", version, "Fredrik"))
+
+	v, _ = d.Get("name")
+	assert.Equal(t, "Fredrik", v.(string))
+
+	d.Flush()
+}
+
+func TestCompareAndSetABA(t *testing.T) {
+	d, _ := Open()
+	defer d.Close()
+	s := d.(driver.CASDriver)
+	d.Flush()
+
+	d.Set("name", "Fredrik")
+	_, version, _ := s.GetWithVersion("name")
+
+	// The value is the same but the key has been written since.
+	d.Set("name", "Elli")
+	d.Set("name", "Fredrik")
+	assert.True(t, errors.Is(s.CompareAndSet("name", version, "Elli"), driver.ErrConflict))
+
+	_, version, _ = s.GetWithVersion("name")
+	d.Delete("name")
+	d.Set("name", "Fredrik")
+	assert.True(t, errors.Is(s.CompareAndSet("name", version, "Elli"), driver.ErrConflict))
+
+	_, version, _ = s.GetWithVersion("name")
+	d.Flush()
+	d.Set("name", "Fredrik")
+	assert.True(t, errors.Is(s.CompareAndSet("name", version, "Elli"), driver.ErrConflict))
+
+	// The versions is not listed as keys.
+	keys, _ := d.Keys()
+	assert.Equal(t, []string{"name"}, keys)
+
+	count, _ := d.Count()
+	assert.Equal(t, int64(1), count)
+
+	d.Flush()
+}
+
+func TestIncrBy(t *testing.T) {
+	d, _ := Open()
+	defer d.Close()
+	s := d.(driver.CounterDriver)
+	d.Flush()
+
+	n, err := s.IncrBy("counter", 5)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(5), n)
+
+	n, err = s.IncrBy("counter", -2)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), n)
+
+	v, err := d.Get("counter")
+	assert.Nil(t, err)
+	assert.Equal(t, float64(3), v.(float64))
+
+	f, err := s.IncrByFloat("counter", 0.5)
+	assert.Nil(t, err)
+	assert.Equal(t, 3.5, f)
+
+	d.Set("name", "Fredrik")
+	_, err = s.IncrBy("name", 1)
+	assert.NotNil(t, err)
+
+	d.Flush()
+}
+
+func TestSetIf(t *testing.T) {
+	d, _ := Open()
+	defer d.Close()
+	s := d.(driver.ConditionalDriver)
+	d.Flush()
+
+	ok, err := s.SetIfPresent("name", "Fredrik")
+	assert.Nil(t, err)
+	assert.False(t, ok)
+
+	ok, err = s.SetIfAbsent("name", "Fredrik")
+	assert.Nil(t, err)
+	assert.True(t, ok)
+
+	ok, err = s.SetIfAbsent("name", "Elli")
+	assert.Nil(t, err)
+	assert.False(t, ok)
+
+	v, _ := d.Get("name")
+	assert.Equal(t, "Fredrik", v.(string))
+
+	ok, err = s.SetIfPresent("name", "Elli")
+	assert.Nil(t, err)
+	assert.True(t, ok)
+
+	v, _ = d.Get("name")
+	assert.Equal(t, "Elli", v.(string))
+
+	d.(driver.TTLDriver).SetWithTTL("session", "x", 50*time.Millisecond)
+	time.Sleep(100 * time.Millisecond)
+
+	ok, err = s.SetIfAbsent("session", "y")
+	assert.Nil(t, err)
+	assert.True(t, ok)
+
+	d.Flush()
+}
+
+func TestWatch(t *testing.T) {
+	d, _ := Open()
+	defer d.Close()
+	d.Flush()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events, err := d.(driver.WatchDriver).Watch(ctx, "user:")
+	assert.Nil(t, err)
+
+	d.Set("post:1", "Hello")
+	d.Set("user:1", "Fredrik")
+	d.Delete("user:1")
+	d.Flush()
+
+	for _, want := range []driver.Event{
+		{Type: driver.EventSet, Key: "user:1"},
+		{Type: driver.EventDelete, Key: "user:1"},
+		{Type: driver.EventFlush},
+	} {
+		select {
+		case e := <-events:
+			assert.Equal(t, want, e)
+		case <-time.After(time.Second):
+			t.Fatalf("no %s event", want.Type)
+		}
+	}
+
+	cancel()
+
+	for range events {
+	}
+}
+
+func TestOpenURL(t *testing.T) {
+	u, _ := url.Parse("buntdb:///tmp/store-bunt-url.db")
+	d, err := openURL(u)
+	assert.Nil(t, err)
+	defer d.Close()
+
+	assert.Nil(t, d.Set("name", "Fredrik"))
+	v, err := d.Get("name")
+	assert.Nil(t, err)
+	assert.Equal(t, "Fredrik", v.(string))
+
+	u, _ = url.Parse("buntdb:///tmp/store-bunt-url.db?sync=always")
+	_, err = openURL(u)
+	assert.True(t, errors.Is(err, driver.ErrInvalidArgs))
+}
+
+func TestCapabilities(t *testing.T) {
+	assert.Equal(t, "context|ttl|batch|tx|scan|match|range|cas|counter|conditional|watch", driver.CapabilitiesOf(&Driver{}).String())
+}
+
+func TestConformance(t *testing.T) {
+	storetest.Run(t, func() driver.Driver {
+		d, _ := Open()
+		return d
+	})
+}
+
+func BenchmarkGet(b *testing.B) {
+	d, _ := Open()
+	defer d.Close()
+	defer d.Flush()
+
+	d.Set("name", "Fredrik")
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := d.Get("name"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSet(b *testing.B) {
+	d, _ := Open()
+	defer d.Close()
+	defer d.Flush()
+
+	for i := 0; i < b.N; i++ {
+		if err := d.Set("name", "Fredrik"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
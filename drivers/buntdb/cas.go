@@ -0,0 +1,163 @@
+package buntdb
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/frozzare/go-store/driver"
+	bunt "github.com/tidwall/buntdb"
+)
+
+const (
+	// reserved is the prefix of the keys the driver stores next to the
+	// values, it's not valid UTF-8 so it does not collide with the keys
+	// and the reserved keys is sorted after them.
+	reserved = "\xffgo-store:"
+
+	// versionPrefix is the prefix of the companion key that
+	// holds the version of a key.
+	versionPrefix = reserved + "version:"
+
+	// sequenceKey holds the last version given to a key. It's kept when
+	// keys is deleted so a key never gets back a earlier version.
+	sequenceKey = reserved + "sequence"
+)
+
+// internal reports if the key is one of the reserved
+// keys, which is left out when the keys is listed.
+func internal(key string) bool {
+	return strings.HasPrefix(key, reserved)
+}
+
+// versionOf returns the version of a key or a empty string if the key
+// does not exist. A key written before the versions was stored has
+// the version 0 until it's written again.
+func versionOf(tx *bunt.Tx, key string) (string, error) {
+	if _, err := tx.Get(key); err == bunt.ErrNotFound {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+
+	v, err := tx.Get(versionPrefix + key)
+
+	if err == bunt.ErrNotFound {
+		return "0", nil
+	}
+
+	return v, err
+}
+
+// bump sets the version of a key to the next sequence, it's
+// called in the same transaction as the value is written.
+func bump(tx *bunt.Tx, key string) error {
+	var seq uint64
+
+	if v, err := tx.Get(sequenceKey); err == nil {
+		seq, _ = strconv.ParseUint(v, 36, 64)
+	} else if err != bunt.ErrNotFound {
+		return err
+	}
+
+	v := strconv.FormatUint(seq+1, 36)
+
+	if _, _, err := tx.Set(sequenceKey, v, nil); err != nil {
+		return err
+	}
+
+	_, _, err := tx.Set(versionPrefix+key, v, nil)
+
+	return err
+}
+
+// unversion removes the version of a deleted key.
+func unversion(tx *bunt.Tx, key string) error {
+	if _, err := tx.Delete(versionPrefix + key); err != nil && err != bunt.ErrNotFound {
+		return err
+	}
+
+	return nil
+}
+
+// GetWithVersion returns the value and version for a key.
+func (s *Driver) GetWithVersion(key string, args ...interface{}) (value interface{}, version string, err error) {
+	if err := driver.CheckArgs(args); err != nil {
+		return nil, "", wrapError("get", key, err)
+	}
+
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	db, err := s.db()
+
+	if err != nil {
+		return nil, "", wrapError("get", key, err)
+	}
+
+	err = db.View(func(tx *bunt.Tx) error {
+		val, err := tx.Get(key)
+
+		if err != nil {
+			return err
+		}
+
+		if version, err = versionOf(tx, key); err != nil {
+			return err
+		}
+
+		value, err = driver.Decode(s.codec, []byte(val), args)
+
+		return err
+	})
+
+	if err != nil {
+		return nil, "", wrapError("get", key, err)
+	}
+
+	return
+}
+
+// CompareAndSet sets key to value if the version of the key is
+// the given version, the version is checked in the write transaction.
+func (s *Driver) CompareAndSet(key, version string, value interface{}) error {
+	data, err := s.codec.Marshal(value)
+
+	if err != nil {
+		return wrapError("cas", key, err)
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	db, err := s.db()
+
+	if err != nil {
+		return wrapError("cas", key, err)
+	}
+
+	err = db.Update(func(tx *bunt.Tx) error {
+		current, err := versionOf(tx, key)
+
+		if err != nil {
+			return err
+		}
+
+		if current != version {
+			return driver.ErrConflict
+		}
+
+		if _, _, err := tx.Set(key, string(data), nil); err != nil {
+			return err
+		}
+
+		return bump(tx, key)
+	})
+
+	if err != nil {
+		return wrapError("cas", key, err)
+	}
+
+	s.hub.Publish(driver.EventSet, key)
+
+	return nil
+}
@@ -0,0 +1,79 @@
+package buntdb
+
+import (
+	"github.com/frozzare/go-store/codec"
+	"github.com/frozzare/go-store/driver"
+)
+
+// Config is the configuration of a BuntDB store.
+type Config struct {
+	// Path is the path of the database file, /tmp/store-bunt.db
+	// by default. Use ":memory:" for a in-memory database.
+	Path string
+
+	// Codec encodes the values, codec.JSON by default.
+	Codec driver.Codec
+}
+
+// Option configures a BuntDB store.
+type Option func(*Config)
+
+// WithPath sets the path of the database file.
+func WithPath(path string) Option {
+	return func(c *Config) {
+		c.Path = path
+	}
+}
+
+// newConfig returns the config for the Open args. The args is a
+// Config, Options, a driver.Codec or the positional path where
+// a nil value keeps the default.
+func newConfig(args []interface{}) (Config, error) {
+	var c Config
+	var i int
+
+	for _, arg := range args {
+		switch v := arg.(type) {
+		case Option:
+			v(&c)
+			continue
+		case Config:
+			c = v
+			continue
+		case *Config:
+			if v != nil {
+				c = *v
+			}
+			continue
+		case driver.Codec:
+			c.Codec = v
+			continue
+		}
+
+		if arg != nil {
+			path, ok := arg.(string)
+
+			if i > 0 {
+				return c, driver.ArgError("buntdb", i, "a Option", arg)
+			}
+
+			if !ok {
+				return c, driver.ArgError("buntdb", i, "a string path", arg)
+			}
+
+			c.Path = path
+		}
+
+		i++
+	}
+
+	if c.Path == "" {
+		c.Path = "/tmp/store-bunt.db"
+	}
+
+	if c.Codec == nil {
+		c.Codec = codec.JSON{}
+	}
+
+	return c, nil
+}
@@ -0,0 +1,78 @@
+package buntdb
+
+import (
+	"github.com/frozzare/go-store/driver"
+	bunt "github.com/tidwall/buntdb"
+)
+
+// modify replaces the data of a key with the data returned by fn in
+// a single write transaction. The data passed to fn is nil if the key
+// does not exist. The ttl of the key is kept.
+func (s *Driver) modify(op, key string, fn func(data []byte) ([]byte, error)) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	db, err := s.db()
+
+	if err != nil {
+		return wrapError(op, key, err)
+	}
+
+	err = db.Update(func(tx *bunt.Tx) error {
+		var data []byte
+		var opts *bunt.SetOptions
+
+		val, err := tx.Get(key)
+
+		switch {
+		case err == nil:
+			data = []byte(val)
+		case err != bunt.ErrNotFound:
+			return err
+		}
+
+		if ttl, err := tx.TTL(key); err == nil && ttl > 0 {
+			opts = &bunt.SetOptions{Expires: true, TTL: ttl}
+		}
+
+		data, err = fn(data)
+
+		if err != nil {
+			return err
+		}
+
+		if _, _, err = tx.Set(key, string(data), opts); err != nil {
+			return err
+		}
+
+		return bump(tx, key)
+	})
+
+	if err != nil {
+		return wrapError(op, key, err)
+	}
+
+	s.hub.Publish(driver.EventSet, key)
+
+	return nil
+}
+
+// IncrBy adds delta to the integer value of key and returns the new value.
+func (s *Driver) IncrBy(key string, delta int64) (n int64, err error) {
+	err = s.modify("incr", key, func(data []byte) (res []byte, err error) {
+		n, res, err = driver.Add(s.codec, data, delta)
+		return
+	})
+
+	return
+}
+
+// IncrByFloat adds delta to the float value of key and returns the new value.
+func (s *Driver) IncrByFloat(key string, delta float64) (n float64, err error) {
+	err = s.modify("incr", key, func(data []byte) (res []byte, err error) {
+		n, res, err = driver.Add(s.codec, data, delta)
+		return
+	})
+
+	return
+}
@@ -0,0 +1,82 @@
+package buntdb
+
+import (
+	"strings"
+
+	"github.com/frozzare/go-store/driver"
+	bunt "github.com/tidwall/buntdb"
+)
+
+// loosen rewrites a glob pattern to the pattern syntax of BuntDB,
+// which only knows about * and ?. Character classes and escaped
+// wildcards is replaced with ?, so the pattern matches at least
+// the keys the glob pattern matches.
+func loosen(pattern string) string {
+	var b strings.Builder
+
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; c {
+		case '[':
+			if end := strings.IndexByte(pattern[i+1:], ']'); end > 0 {
+				b.WriteByte('?')
+				i += end + 1
+				continue
+			}
+		case '\\':
+			if i+1 < len(pattern) {
+				i++
+
+				if c := pattern[i]; c == '*' || c == '?' {
+					b.WriteByte('?')
+					continue
+				}
+			}
+		}
+
+		b.WriteByte(pattern[i])
+	}
+
+	return b.String()
+}
+
+// match returns the keys that matches the pattern. AscendKeys
+// finds the keys for the loosened pattern and they is matched
+// against the pattern.
+func (s *Driver) match(op, pattern string) (keys []string, err error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	db, err := s.db()
+
+	if err != nil {
+		return nil, wrapError(op, "", err)
+	}
+
+	err = db.View(func(tx *bunt.Tx) error {
+		return tx.AscendKeys(loosen(pattern), func(key, value string) bool {
+			if !internal(key) && driver.Match(pattern, key) && !expired(tx, key) {
+				keys = append(keys, key)
+			}
+
+			return true
+		})
+	})
+
+	if err != nil {
+		return nil, wrapError(op, "", err)
+	}
+
+	return
+}
+
+// KeysMatch returns the keys that matches the pattern.
+func (s *Driver) KeysMatch(pattern string) ([]string, error) {
+	return s.match("keys", pattern)
+}
+
+// CountMatch returns the number of keys that matches the pattern.
+func (s *Driver) CountMatch(pattern string) (int64, error) {
+	keys, err := s.match("count", pattern)
+
+	return int64(len(keys)), err
+}
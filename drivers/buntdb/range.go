@@ -0,0 +1,70 @@
+package buntdb
+
+import (
+	"github.com/frozzare/go-store/driver"
+	bunt "github.com/tidwall/buntdb"
+)
+
+// Range returns up to limit keys and values where start <= key < end
+// using the BuntDB key order.
+func (s *Driver) Range(start, end string, limit int, reverse bool) (pairs []driver.KV, err error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	db, err := s.db()
+
+	if err != nil {
+		return nil, wrapError("range", "", err)
+	}
+
+	err = db.View(func(tx *bunt.Tx) error {
+		var derr error
+
+		iterator := func(key, value string) bool {
+			if key < start || (end != "" && key >= end) {
+				// The first key can be the end key when iterating in reverse.
+				return reverse && key == end
+			}
+
+			if internal(key) || expired(tx, key) {
+				return true
+			}
+
+			v, err := driver.Decode(s.codec, []byte(value), nil)
+
+			if err != nil {
+				derr = err
+				return false
+			}
+
+			pairs = append(pairs, driver.KV{Key: key, Value: v})
+
+			return limit < 1 || len(pairs) < limit
+		}
+
+		var err error
+
+		switch {
+		case reverse && end == "":
+			err = tx.Descend("", iterator)
+		case reverse:
+			err = tx.DescendLessOrEqual("", end, iterator)
+		case end == "":
+			err = tx.AscendGreaterOrEqual("", start, iterator)
+		default:
+			err = tx.AscendRange("", start, end, iterator)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		return derr
+	})
+
+	if err != nil {
+		return nil, wrapError("range", "", err)
+	}
+
+	return
+}
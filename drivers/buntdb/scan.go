@@ -0,0 +1,54 @@
+package buntdb
+
+import (
+	"strings"
+
+	bunt "github.com/tidwall/buntdb"
+)
+
+// Scan returns up to limit keys with the prefix starting at the cursor.
+// The keys is sorted, so the cursor is the last key that was returned.
+func (s *Driver) Scan(prefix, cursor string, limit int) (keys []string, next string, err error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	db, err := s.db()
+
+	if err != nil {
+		return nil, "", wrapError("scan", "", err)
+	}
+
+	start := prefix
+	if cursor > prefix {
+		start = cursor
+	}
+
+	err = db.View(func(tx *bunt.Tx) error {
+		return tx.AscendGreaterOrEqual("", start, func(key, value string) bool {
+			if !strings.HasPrefix(key, prefix) {
+				return false
+			}
+
+			if key == cursor || internal(key) || expired(tx, key) {
+				return true
+			}
+
+			// One more key than the limit is read to know
+			// if there is more keys left.
+			if limit > 0 && len(keys) == limit {
+				next = keys[limit-1]
+				return false
+			}
+
+			keys = append(keys, key)
+
+			return true
+		})
+	})
+
+	if err != nil {
+		return nil, "", wrapError("scan", "", err)
+	}
+
+	return
+}
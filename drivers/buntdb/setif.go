@@ -0,0 +1,67 @@
+package buntdb
+
+import (
+	"github.com/frozzare/go-store/driver"
+	bunt "github.com/tidwall/buntdb"
+)
+
+// setIf sets key to value if the existence of the key
+// is the same as present in a single write transaction.
+func (s *Driver) setIf(key string, value interface{}, present bool) (bool, error) {
+	data, err := s.codec.Marshal(value)
+
+	if err != nil {
+		return false, wrapError("set", key, err)
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	db, err := s.db()
+
+	if err != nil {
+		return false, wrapError("set", key, err)
+	}
+
+	ok := false
+
+	err = db.Update(func(tx *bunt.Tx) error {
+		_, err := tx.Get(key)
+
+		if err != nil && err != bunt.ErrNotFound {
+			return err
+		}
+
+		if exists := err == nil; exists != present {
+			return nil
+		}
+
+		ok = true
+
+		if _, _, err = tx.Set(key, string(data), nil); err != nil {
+			return err
+		}
+
+		return bump(tx, key)
+	})
+
+	if err != nil {
+		return false, wrapError("set", key, err)
+	}
+
+	if ok {
+		s.hub.Publish(driver.EventSet, key)
+	}
+
+	return ok, nil
+}
+
+// SetIfAbsent sets key to value if the key does not exist.
+func (s *Driver) SetIfAbsent(key string, value interface{}) (bool, error) {
+	return s.setIf(key, value, false)
+}
+
+// SetIfPresent sets key to value if the key exist.
+func (s *Driver) SetIfPresent(key string, value interface{}) (bool, error) {
+	return s.setIf(key, value, true)
+}
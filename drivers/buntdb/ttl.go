@@ -0,0 +1,154 @@
+package buntdb
+
+import (
+	"time"
+
+	"github.com/frozzare/go-store/driver"
+	bunt "github.com/tidwall/buntdb"
+)
+
+// expired reports if the key has expired but not yet been
+// evicted, since Ascend still returns those keys.
+func expired(tx *bunt.Tx, key string) bool {
+	_, err := tx.TTL(key)
+
+	return err == bunt.ErrNotFound
+}
+
+// expire sets the key with a ttl. BuntDB skips expired records when
+// the file is loaded without removing the earlier value of the key,
+// so the key is deleted first to not bring the old value back when
+// the file is reopened. The key is written again, so the version of
+// the key is changed too.
+func expire(db *bunt.DB, key, value string, ttl time.Duration) error {
+	err := db.Update(func(tx *bunt.Tx) error {
+		_, err := tx.Delete(key)
+
+		return err
+	})
+
+	if err != nil && err != bunt.ErrNotFound {
+		return err
+	}
+
+	return db.Update(func(tx *bunt.Tx) error {
+		if _, _, err := tx.Set(key, value, &bunt.SetOptions{Expires: true, TTL: ttl}); err != nil {
+			return err
+		}
+
+		return bump(tx, key)
+	})
+}
+
+// SetWithTTL key with value in store that expires after the ttl.
+func (s *Driver) SetWithTTL(key string, value interface{}, ttl time.Duration) error {
+	if ttl <= 0 {
+		return wrapError("set", key, driver.ErrInvalidArgs)
+	}
+
+	data, err := s.codec.Marshal(value)
+
+	if err != nil {
+		return wrapError("set", key, err)
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	db, err := s.db()
+
+	if err != nil {
+		return wrapError("set", key, err)
+	}
+
+	if err := expire(db, key, string(data), ttl); err != nil {
+		return wrapError("set", key, err)
+	}
+
+	s.hub.Publish(driver.EventSet, key)
+
+	return nil
+}
+
+// TTL returns the time left before the key expires
+// or NoExpiration if the key does not expire.
+func (s *Driver) TTL(key string) (ttl time.Duration, err error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	db, err := s.db()
+
+	if err != nil {
+		return 0, wrapError("ttl", key, err)
+	}
+
+	err = db.View(func(tx *bunt.Tx) error {
+		ttl, err = tx.TTL(key)
+
+		if ttl < 0 {
+			ttl = driver.NoExpiration
+		}
+
+		return err
+	})
+
+	if err != nil {
+		return 0, wrapError("ttl", key, err)
+	}
+
+	return
+}
+
+// Expire sets the ttl for a existing key.
+func (s *Driver) Expire(key string, ttl time.Duration) error {
+	if ttl <= 0 {
+		return wrapError("expire", key, driver.ErrInvalidArgs)
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	db, err := s.db()
+
+	if err != nil {
+		return wrapError("expire", key, err)
+	}
+
+	var value string
+
+	err = db.View(func(tx *bunt.Tx) (err error) {
+		value, err = tx.Get(key)
+
+		return
+	})
+
+	if err != nil {
+		return wrapError("expire", key, err)
+	}
+
+	return wrapError("expire", key, expire(db, key, value, ttl))
+}
+
+// Persist removes the expiration from a existing key.
+func (s *Driver) Persist(key string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	db, err := s.db()
+
+	if err != nil {
+		return wrapError("persist", key, err)
+	}
+
+	return wrapError("persist", key, db.Update(func(tx *bunt.Tx) error {
+		value, err := tx.Get(key)
+
+		if err != nil {
+			return err
+		}
+
+		_, _, err = tx.Set(key, value, nil)
+
+		return err
+	}))
+}
@@ -0,0 +1,128 @@
+package buntdb
+
+import (
+	"github.com/frozzare/go-store/driver"
+	bunt "github.com/tidwall/buntdb"
+)
+
+// tx represents a BuntDB transaction.
+type tx struct {
+	s      *Driver
+	tx     *bunt.Tx
+	events []driver.Event
+}
+
+// Exists returns true when a key exists false when not existing in store.
+func (t *tx) Exists(key string) (bool, error) {
+	_, err := t.tx.Get(key)
+
+	if err == bunt.ErrNotFound {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, wrapError("exists", key, err)
+	}
+
+	return true, nil
+}
+
+// Get returns the value for a key if any.
+func (t *tx) Get(key string, args ...interface{}) (interface{}, error) {
+	if err := driver.CheckArgs(args); err != nil {
+		return nil, wrapError("get", key, err)
+	}
+
+	val, err := t.tx.Get(key)
+
+	if err != nil {
+		return nil, wrapError("get", key, err)
+	}
+
+	value, err := driver.Decode(t.s.codec, []byte(val), args)
+
+	if err != nil {
+		return nil, wrapError("get", key, err)
+	}
+
+	return value, nil
+}
+
+// Set key with value in store.
+func (t *tx) Set(key string, value interface{}) error {
+	data, err := t.s.codec.Marshal(value)
+
+	if err != nil {
+		return wrapError("set", key, err)
+	}
+
+	if _, _, err = t.tx.Set(key, string(data), nil); err != nil {
+		return wrapError("set", key, err)
+	}
+
+	if err := bump(t.tx, key); err != nil {
+		return wrapError("set", key, err)
+	}
+
+	t.events = append(t.events, driver.Event{Type: driver.EventSet, Key: key})
+
+	return nil
+}
+
+// Delete key from store.
+func (t *tx) Delete(key string) error {
+	_, err := t.tx.Delete(key)
+
+	if err == bunt.ErrNotFound {
+		return nil
+	}
+
+	if err != nil {
+		return wrapError("delete", key, err)
+	}
+
+	if err := unversion(t.tx, key); err != nil {
+		return wrapError("delete", key, err)
+	}
+
+	t.events = append(t.events, driver.Event{Type: driver.EventDelete, Key: key})
+
+	return nil
+}
+
+// Update runs fn in a BuntDB write transaction. The changes is
+// committed if fn returns nil and rolled back if not.
+func (s *Driver) Update(fn func(tx driver.Tx) error) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	db, err := s.db()
+
+	if err != nil {
+		return wrapError("update", "", err)
+	}
+
+	var events []driver.Event
+
+	err = db.Update(func(btx *bunt.Tx) error {
+		t := &tx{s: s, tx: btx}
+
+		if err := fn(t); err != nil {
+			return err
+		}
+
+		events = t.events
+
+		return nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	for _, e := range events {
+		s.hub.Publish(e.Type, e.Key)
+	}
+
+	return nil
+}
@@ -0,0 +1,28 @@
+package buntdb
+
+import (
+	"net/url"
+
+	"github.com/frozzare/go-store/driver"
+)
+
+// init registers the buntdb:// url scheme.
+func init() {
+	driver.RegisterURL("buntdb", openURL)
+}
+
+// openURL opens a store from a buntdb:///path/to/file.db or a
+// buntdb://:memory: url. The only query parameter is codec.
+func openURL(u *url.URL) (driver.Driver, error) {
+	if err := driver.URLParams(u, "codec"); err != nil {
+		return nil, err
+	}
+
+	codec, err := driver.URLCodec(u)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return Open(codec, WithPath(driver.URLPath(u)))
+}
@@ -0,0 +1,32 @@
+package buntdb
+
+import (
+	"context"
+
+	"github.com/frozzare/go-store/driver"
+	bunt "github.com/tidwall/buntdb"
+)
+
+// onExpired deletes a expired key and sends a Expire event, it's
+// called by BuntDB in the transaction that removes expired keys.
+func (s *Driver) onExpired(key, value string, tx *bunt.Tx) error {
+	if _, err := tx.Delete(key); err != nil && err != bunt.ErrNotFound {
+		return err
+	}
+
+	if err := unversion(tx, key); err != nil {
+		return err
+	}
+
+	s.hub.Publish(driver.EventExpire, key)
+
+	return nil
+}
+
+// Watch returns a channel that receives events for keys with the
+// prefix until the context is done. Only the changes made through
+// this driver is sent, and Expire events is sent when BuntDB removes
+// the expired keys while the database is open.
+func (s *Driver) Watch(ctx context.Context, prefix string) (<-chan driver.Event, error) {
+	return s.hub.Watch(ctx, prefix), nil
+}
@@ -0,0 +1,104 @@
+package leveldb
+
+import (
+	"time"
+
+	"github.com/frozzare/go-store/driver"
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+// GetMulti returns the values for the keys that exists in store.
+func (s *Driver) GetMulti(keys []string, args ...interface{}) (map[string]interface{}, error) {
+	if err := driver.CheckArgs(args); err != nil {
+		return nil, wrapError("getmulti", "", err)
+	}
+
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	db, err := s.db()
+
+	if err != nil {
+		return nil, wrapError("getmulti", "", err)
+	}
+
+	now := time.Now()
+
+	values, err := driver.DecodeMulti(keys, args, func(key string, v interface{}) error {
+		res, err := db.Get([]byte(key), nil)
+
+		if err == leveldb.ErrNotFound {
+			return driver.ErrNotFound
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if expired, err := s.expired(db, key, now); err != nil || expired {
+			if err == nil {
+				err = driver.ErrNotFound
+			}
+
+			return err
+		}
+
+		return driver.Unmarshal(s.codec, res, v)
+	})
+
+	if err != nil {
+		return nil, wrapError("getmulti", "", err)
+	}
+
+	return values, nil
+}
+
+// SetMulti keys with values in store.
+func (s *Driver) SetMulti(values map[string]interface{}) error {
+	batch := new(leveldb.Batch)
+	keys := make([]string, 0, len(values))
+
+	for key, value := range values {
+		data, err := s.codec.Marshal(value)
+
+		if err != nil {
+			return wrapError("setmulti", key, err)
+		}
+
+		batch.Put([]byte(key), data)
+		batch.Delete(ttlKey(key))
+		keys = append(keys, key)
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	db, err := s.db()
+
+	if err != nil {
+		return wrapError("setmulti", "", err)
+	}
+
+	return wrapError("setmulti", "", s.write(db, batch, driver.EventSet, keys...))
+}
+
+// DeleteMulti keys from store.
+func (s *Driver) DeleteMulti(keys ...string) error {
+	batch := new(leveldb.Batch)
+
+	for _, key := range keys {
+		batch.Delete([]byte(key))
+		batch.Delete(ttlKey(key))
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	db, err := s.db()
+
+	if err != nil {
+		return wrapError("deletemulti", "", err)
+	}
+
+	return wrapError("deletemulti", "", s.write(db, batch, driver.EventDelete, keys...))
+}
@@ -0,0 +1,144 @@
+package leveldb
+
+import (
+	"strconv"
+
+	"github.com/frozzare/go-store/driver"
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+const (
+	// versionPrefix is the prefix of the companion key that
+	// holds the version of a key.
+	versionPrefix = reserved + "version:"
+
+	// sequenceKey holds the last version given to a key. It's kept when
+	// keys is deleted so a key never gets back a earlier version.
+	sequenceKey = reserved + "sequence"
+)
+
+// versionKey returns the key that holds the version of a key.
+func versionKey(key string) []byte {
+	return []byte(versionPrefix + key)
+}
+
+// version returns the version of a key or a empty string if the key
+// does not exist. A key written before the versions was stored has
+// the version 0 until it's written again.
+func (s *Driver) version(db reader, key string) (string, error) {
+	if exists, err := s.exists(db, key); err != nil || !exists {
+		return "", err
+	}
+
+	v, err := db.Get(versionKey(key), nil)
+
+	if err == leveldb.ErrNotFound {
+		return "0", nil
+	}
+
+	return string(v), err
+}
+
+// bump puts the next sequences as the versions of the keys
+// in the batch, so they is written together with the values.
+func bump(db reader, batch *leveldb.Batch, keys ...string) error {
+	var seq uint64
+
+	if v, err := db.Get([]byte(sequenceKey), nil); err == nil {
+		seq, _ = strconv.ParseUint(string(v), 36, 64)
+	} else if err != leveldb.ErrNotFound {
+		return err
+	}
+
+	for _, key := range keys {
+		seq++
+		batch.Put(versionKey(key), []byte(strconv.FormatUint(seq, 36)))
+	}
+
+	batch.Put([]byte(sequenceKey), []byte(strconv.FormatUint(seq, 36)))
+
+	return nil
+}
+
+// unversion removes the versions of deleted keys in the batch.
+func unversion(batch *leveldb.Batch, keys ...string) {
+	for _, key := range keys {
+		batch.Delete(versionKey(key))
+	}
+}
+
+// GetWithVersion returns the value and version for a key.
+func (s *Driver) GetWithVersion(key string, args ...interface{}) (interface{}, string, error) {
+	if err := driver.CheckArgs(args); err != nil {
+		return nil, "", wrapError("get", key, err)
+	}
+
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	db, err := s.db()
+
+	if err != nil {
+		return nil, "", wrapError("get", key, err)
+	}
+
+	res, err := db.Get([]byte(key), nil)
+
+	if err != nil {
+		return nil, "", wrapError("get", key, err)
+	}
+
+	version, err := s.version(db, key)
+
+	if err == nil && version == "" {
+		err = driver.ErrNotFound
+	}
+
+	if err != nil {
+		return nil, "", wrapError("get", key, err)
+	}
+
+	value, err := driver.Decode(s.codec, res, args)
+
+	if err != nil {
+		return nil, "", wrapError("get", key, err)
+	}
+
+	return value, version, nil
+}
+
+// CompareAndSet sets key to value if the version of the key is
+// the given version. The driver lock is held while the version
+// is checked and the value is written.
+func (s *Driver) CompareAndSet(key, version string, value interface{}) error {
+	data, err := s.codec.Marshal(value)
+
+	if err != nil {
+		return wrapError("cas", key, err)
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	db, err := s.db()
+
+	if err != nil {
+		return wrapError("cas", key, err)
+	}
+
+	current, err := s.version(db, key)
+
+	if err != nil {
+		return wrapError("cas", key, err)
+	}
+
+	if current != version {
+		return wrapError("cas", key, driver.ErrConflict)
+	}
+
+	batch := new(leveldb.Batch)
+	batch.Put([]byte(key), data)
+	batch.Delete(ttlKey(key))
+
+	return wrapError("cas", key, s.write(db, batch, driver.EventSet, key))
+}
@@ -0,0 +1,99 @@
+package leveldb
+
+import (
+	"github.com/frozzare/go-store/codec"
+	"github.com/frozzare/go-store/driver"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+)
+
+// Config is the configuration of a LevelDB store.
+type Config struct {
+	// Path is the path of the database directory, /tmp/store.leveldb by default.
+	Path string
+
+	// Options is passed to leveldb.OpenFile.
+	Options *opt.Options
+
+	// Codec encodes the values, codec.JSON by default.
+	Codec driver.Codec
+}
+
+// Option configures a LevelDB store.
+type Option func(*Config)
+
+// WithPath sets the path of the database directory.
+func WithPath(path string) Option {
+	return func(c *Config) {
+		c.Path = path
+	}
+}
+
+// WithOptions sets the options that is passed to leveldb.OpenFile.
+func WithOptions(options *opt.Options) Option {
+	return func(c *Config) {
+		c.Options = options
+	}
+}
+
+// newConfig returns the config for the Open args. The args is a
+// Config, Options, a driver.Codec or the positional path and
+// *opt.Options where a nil value keeps the default.
+func newConfig(args []interface{}) (Config, error) {
+	var c Config
+	var i int
+
+	for _, arg := range args {
+		switch v := arg.(type) {
+		case Option:
+			v(&c)
+			continue
+		case Config:
+			c = v
+			continue
+		case *Config:
+			if v != nil {
+				c = *v
+			}
+			continue
+		case driver.Codec:
+			c.Codec = v
+			continue
+		}
+
+		if arg != nil {
+			switch i {
+			case 0:
+				path, ok := arg.(string)
+
+				if !ok {
+					return c, driver.ArgError("leveldb", i, "a string path", arg)
+				}
+
+				c.Path = path
+			case 1:
+				switch v := arg.(type) {
+				case *opt.Options:
+					c.Options = v
+				case opt.Options:
+					c.Options = &v
+				default:
+					return c, driver.ArgError("leveldb", i, "a *opt.Options", arg)
+				}
+			default:
+				return c, driver.ArgError("leveldb", i, "a Option", arg)
+			}
+		}
+
+		i++
+	}
+
+	if c.Path == "" {
+		c.Path = "/tmp/store.leveldb"
+	}
+
+	if c.Codec == nil {
+		c.Codec = codec.JSON{}
+	}
+
+	return c, nil
+}
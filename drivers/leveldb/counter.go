@@ -0,0 +1,76 @@
+package leveldb
+
+import (
+	"time"
+
+	"github.com/frozzare/go-store/driver"
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+// modify replaces the data of a key with the data returned by fn
+// while holding the driver lock. The data passed to fn is nil if the
+// key does not exist. The ttl of the key is kept.
+func (s *Driver) modify(op, key string, fn func(data []byte) ([]byte, error)) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	db, err := s.db()
+
+	if err != nil {
+		return wrapError(op, key, err)
+	}
+
+	batch := new(leveldb.Batch)
+	data, err := db.Get([]byte(key), nil)
+
+	if err == leveldb.ErrNotFound {
+		data, err = nil, nil
+	}
+
+	if err != nil {
+		return wrapError(op, key, err)
+	}
+
+	if data != nil {
+		expired, err := s.expired(db, key, time.Now())
+
+		if err != nil {
+			return wrapError(op, key, err)
+		}
+
+		if expired {
+			data = nil
+			batch.Delete(ttlKey(key))
+		}
+	}
+
+	data, err = fn(data)
+
+	if err != nil {
+		return wrapError(op, key, err)
+	}
+
+	batch.Put([]byte(key), data)
+
+	return wrapError(op, key, s.write(db, batch, driver.EventSet, key))
+}
+
+// IncrBy adds delta to the integer value of key and returns the new value.
+func (s *Driver) IncrBy(key string, delta int64) (n int64, err error) {
+	err = s.modify("incr", key, func(data []byte) (res []byte, err error) {
+		n, res, err = driver.Add(s.codec, data, delta)
+		return
+	})
+
+	return
+}
+
+// IncrByFloat adds delta to the float value of key and returns the new value.
+func (s *Driver) IncrByFloat(key string, delta float64) (n float64, err error) {
+	err = s.modify("incr", key, func(data []byte) (res []byte, err error) {
+		n, res, err = driver.Add(s.codec, data, delta)
+		return
+	})
+
+	return
+}
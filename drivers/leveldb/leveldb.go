@@ -1,42 +1,47 @@
 package leveldb
 
 import (
-	"encoding/json"
-	"reflect"
+	"context"
+	"sync"
+	"time"
 
 	"github.com/frozzare/go-store/driver"
 	"github.com/syndtr/goleveldb/leveldb"
-	"github.com/syndtr/goleveldb/leveldb/opt"
 )
 
 // Driver represents a BoltDB driver.
 type Driver struct {
-	args   []interface{}
-	closed bool
-	client *leveldb.DB
+	config  Config
+	closed  bool
+	client  *leveldb.DB
+	codec   driver.Codec
+	janitor *driver.Janitor
+	hub     driver.Hub
+
+	// lock is read locked by the calls that reads and write locked
+	// by the calls that writes, so Close waits for the calls in flight.
+	lock sync.RWMutex
+
+	// open serializes the calls that opens the client.
+	open sync.Mutex
 }
 
 // db returns the LevelDB client if existing
-// or creating a new if closed.
+// or opening it on the first call, ErrClosed is
+// returned after Close.
 func (s *Driver) db() (*leveldb.DB, error) {
-	if s.client != nil && !s.closed {
-		return s.client, nil
-	}
-
-	s.closed = false
+	s.open.Lock()
+	defer s.open.Unlock()
 
-	path := "/tmp/store.leveldb"
-	var options *opt.Options
-
-	if len(s.args) > 0 && s.args[0] != nil {
-		path = s.args[0].(string)
+	if s.closed {
+		return nil, driver.ErrClosed
 	}
 
-	if len(s.args) > 1 && s.args[1] != nil {
-		options = s.args[1].(*opt.Options)
+	if s.client != nil {
+		return s.client, nil
 	}
 
-	client, err := leveldb.OpenFile(path, options)
+	client, err := leveldb.OpenFile(s.config.Path, s.config.Options)
 
 	if err != nil {
 		return nil, err
@@ -47,9 +52,25 @@ func (s *Driver) db() (*leveldb.DB, error) {
 	return client, nil
 }
 
-// Open creates a new LevelDB store.
+// init registers the driver with the name leveldb.
+func init() {
+	driver.RegisterFactory("leveldb", Open)
+}
+
+// Open creates a new LevelDB store. The args is a Config, Options
+// such as WithPath, a driver.Codec or the positional path and
+// *opt.Options.
 func Open(args ...interface{}) (driver.Driver, error) {
-	return &Driver{args: args}, nil
+	config, err := newConfig(args)
+
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Driver{config: config, codec: config.Codec}
+	s.janitor = &driver.Janitor{Purge: s.purge}
+
+	return s, nil
 }
 
 // Open creates a new LevelDB store with a specified instance.
@@ -57,26 +78,74 @@ func (s *Driver) Open(args ...interface{}) (driver.Driver, error) {
 	return Open(args...)
 }
 
+// Capabilities returns the optional features the driver supports.
+func (s *Driver) Capabilities() driver.Capabilities {
+	return driver.CapContext | driver.CapTTL | driver.CapBatch | driver.CapTx |
+		driver.CapScan | driver.CapMatch | driver.CapRange | driver.CapCAS |
+		driver.CapCounter | driver.CapConditional | driver.CapWatch
+}
+
+// wrapError maps LevelDB errors onto the driver errors
+// and records the operation and key.
+func wrapError(op, key string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	switch err {
+	case leveldb.ErrNotFound:
+		err = driver.ErrNotFound
+	case leveldb.ErrClosed:
+		err = driver.ErrClosed
+	}
+
+	return &driver.Error{Op: op, Key: key, Driver: "leveldb", Err: err}
+}
+
 // Count returns numbers of keys in store.
-func (s *Driver) Count() (count int64, err error) {
-	defer s.Close()
+func (s *Driver) Count() (int64, error) {
+	return s.CountContext(context.Background())
+}
+
+// CountContext returns numbers of keys in store.
+func (s *Driver) CountContext(ctx context.Context) (count int64, err error) {
+	if err := ctx.Err(); err != nil {
+		return 0, wrapError("count", "", err)
+	}
+
+	s.lock.RLock()
+	defer s.lock.RUnlock()
 
 	db, err := s.db()
 
 	if err != nil {
-		return
+		return 0, wrapError("count", "", err)
+	}
+
+	expires, err := s.expires(db)
+
+	if err != nil {
+		return 0, wrapError("count", "", err)
 	}
 
 	iter := db.NewIterator(nil, nil)
+	now := time.Now()
 
 	for iter.Next() {
-		count++
+		if err := ctx.Err(); err != nil {
+			iter.Release()
+			return 0, wrapError("count", "", err)
+		}
+
+		if !internal(iter.Key()) && !expired(expires, string(iter.Key()), now) {
+			count++
+		}
 	}
 
 	iter.Release()
 
 	if err := iter.Error(); err != nil {
-		return 0, err
+		return 0, wrapError("count", "", err)
 	}
 
 	return
@@ -84,16 +153,28 @@ func (s *Driver) Count() (count int64, err error) {
 
 // Exists returns true when a key exists false when not existing in store.
 func (s *Driver) Exists(key string) (bool, error) {
+	return s.ExistsContext(context.Background(), key)
+}
+
+// ExistsContext returns true when a key exists false when not existing in store.
+func (s *Driver) ExistsContext(ctx context.Context, key string) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, wrapError("exists", key, err)
+	}
+
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
 	db, err := s.db()
 
 	if err != nil {
-		return false, err
+		return false, wrapError("exists", key, err)
 	}
 
-	exists, err := db.Has([]byte(key), nil)
+	exists, err := s.exists(db, key)
 
 	if err != nil {
-		return false, err
+		return false, wrapError("exists", key, err)
 	}
 
 	return exists, nil
@@ -101,59 +182,97 @@ func (s *Driver) Exists(key string) (bool, error) {
 
 // Get returns the value for a key if any.
 func (s *Driver) Get(key string, args ...interface{}) (interface{}, error) {
-	defer s.Close()
+	return s.GetContext(context.Background(), key, args...)
+}
+
+// GetContext returns the value for a key if any.
+func (s *Driver) GetContext(ctx context.Context, key string, args ...interface{}) (interface{}, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, wrapError("get", key, err)
+	}
+
+	if err := driver.CheckArgs(args); err != nil {
+		return nil, wrapError("get", key, err)
+	}
+
+	s.lock.RLock()
+	defer s.lock.RUnlock()
 
 	db, err := s.db()
 
 	if err != nil {
-		return nil, err
+		return nil, wrapError("get", key, err)
 	}
 
 	res, err := db.Get([]byte(key), nil)
 
 	if err != nil {
-		return nil, err
+		return nil, wrapError("get", key, err)
 	}
 
-	var value interface{}
+	if expired, err := s.expired(db, key, time.Now()); err != nil || expired {
+		if err == nil {
+			err = driver.ErrNotFound
+		}
 
-	if len(args) > 0 {
-		value = args[0]
+		return nil, wrapError("get", key, err)
 	}
 
-	if err = json.Unmarshal([]byte(res), &value); err == nil {
-		if len(args) > 0 {
-			return nil, nil
-		}
+	value, err := driver.Decode(s.codec, res, args)
 
-		return value, nil
+	if err != nil {
+		return nil, wrapError("get", key, err)
 	}
 
-	return string(res), nil
+	return value, nil
 }
 
 // Keys returns a string slice with all keys.
 func (s *Driver) Keys() ([]string, error) {
-	defer s.Close()
+	return s.KeysContext(context.Background())
+}
+
+// KeysContext returns a string slice with all keys.
+func (s *Driver) KeysContext(ctx context.Context) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return []string{}, wrapError("keys", "", err)
+	}
+
+	s.lock.RLock()
+	defer s.lock.RUnlock()
 
 	db, err := s.db()
 
 	if err != nil {
-		return []string{}, err
+		return []string{}, wrapError("keys", "", err)
+	}
+
+	expires, err := s.expires(db)
+
+	if err != nil {
+		return []string{}, wrapError("keys", "", err)
 	}
 
 	iter := db.NewIterator(nil, nil)
+	now := time.Now()
 
 	var keys []string
 
 	for iter.Next() {
-		keys = append(keys, string(iter.Key()))
+		if err := ctx.Err(); err != nil {
+			iter.Release()
+			return []string{}, wrapError("keys", "", err)
+		}
+
+		if key := string(iter.Key()); !internal(iter.Key()) && !expired(expires, key, now) {
+			keys = append(keys, key)
+		}
 	}
 
 	iter.Release()
 
 	if err := iter.Error(); err != nil {
-		return []string{}, err
+		return []string{}, wrapError("keys", "", err)
 	}
 
 	return keys, nil
@@ -161,76 +280,136 @@ func (s *Driver) Keys() ([]string, error) {
 
 // Set key with value in store.
 func (s *Driver) Set(key string, value interface{}) error {
-	defer s.Close()
+	return s.SetContext(context.Background(), key, value)
+}
 
-	db, err := s.db()
+// SetContext key with value in store.
+func (s *Driver) SetContext(ctx context.Context, key string, value interface{}) error {
+	if err := ctx.Err(); err != nil {
+		return wrapError("set", key, err)
+	}
+
+	data, err := s.codec.Marshal(value)
 
 	if err != nil {
-		return err
+		return wrapError("set", key, err)
 	}
 
-	if reflect.TypeOf(value).Kind() != reflect.String {
-		value, err := json.Marshal(value)
+	s.lock.Lock()
+	defer s.lock.Unlock()
 
-		if err != nil {
-			return err
-		}
+	db, err := s.db()
 
-		return db.Put([]byte(key), value, nil)
+	if err != nil {
+		return wrapError("set", key, err)
 	}
 
-	return db.Put([]byte(key), []byte(value.(string)), nil)
+	batch := new(leveldb.Batch)
+	batch.Put([]byte(key), data)
+	batch.Delete(ttlKey(key))
+
+	return wrapError("set", key, s.write(db, batch, driver.EventSet, key))
 }
 
 // Delete key from store.
 func (s *Driver) Delete(key string) error {
-	defer s.Close()
+	return s.DeleteContext(context.Background(), key)
+}
+
+// DeleteContext key from store.
+func (s *Driver) DeleteContext(ctx context.Context, key string) error {
+	if err := ctx.Err(); err != nil {
+		return wrapError("delete", key, err)
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
 
 	db, err := s.db()
 
 	if err != nil {
-		return err
+		return wrapError("delete", key, err)
 	}
 
-	return db.Delete([]byte(key), nil)
+	batch := new(leveldb.Batch)
+	batch.Delete([]byte(key))
+	batch.Delete(ttlKey(key))
+
+	return wrapError("delete", key, s.write(db, batch, driver.EventDelete, key))
 }
 
-// Close will close the boltdb client.
+// Close waits for the calls in flight and closes the database,
+// the calls made after Close returns ErrClosed.
 func (s *Driver) Close() error {
-	db, err := s.db()
+	err := s.close()
 
-	if err != nil {
-		return err
-	}
+	// The janitor is stopped when the lock is released,
+	// since a running purge waits for it.
+	s.janitor.Stop()
+	s.hub.Close()
 
-	err = db.Close()
+	return err
+}
 
-	if err != nil {
-		return err
+// close marks the driver as closed and closes the client.
+func (s *Driver) close() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.closed {
+		return nil
 	}
 
 	s.closed = true
 
-	return nil
+	if s.client == nil {
+		return nil
+	}
+
+	return wrapError("close", "", s.client.Close())
 }
 
 // Flush will remove all keys and values from the store.
 func (s *Driver) Flush() error {
+	return s.FlushContext(context.Background())
+}
+
+// FlushContext will remove all keys and values from the store.
+func (s *Driver) FlushContext(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return wrapError("flush", "", err)
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
 	db, err := s.db()
 
 	if err != nil {
-		return err
+		return wrapError("flush", "", err)
 	}
 
-	defer s.Close()
-
 	iter := db.NewIterator(nil, nil)
 
 	for iter.Next() {
-		db.Delete(iter.Key(), nil)
+		if err := ctx.Err(); err != nil {
+			iter.Release()
+			return wrapError("flush", "", err)
+		}
+
+		// The sequence is kept so a key never gets back a earlier version.
+		if string(iter.Key()) != sequenceKey {
+			db.Delete(iter.Key(), nil)
+		}
 	}
 
 	iter.Release()
 
+	if err := iter.Error(); err != nil {
+		return wrapError("flush", "", err)
+	}
+
+	s.hub.Publish(driver.EventFlush)
+
 	return nil
 }
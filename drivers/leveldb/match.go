@@ -0,0 +1,60 @@
+package leveldb
+
+import (
+	"time"
+
+	"github.com/frozzare/go-store/driver"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+// match returns the keys that matches the pattern. The keys with
+// the literal prefix of the pattern is matched against the pattern.
+func (s *Driver) match(op, pattern string) ([]string, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	db, err := s.db()
+
+	if err != nil {
+		return nil, wrapError(op, "", err)
+	}
+
+	expires, err := s.expires(db)
+
+	if err != nil {
+		return nil, wrapError(op, "", err)
+	}
+
+	iter := db.NewIterator(util.BytesPrefix([]byte(driver.MatchPrefix(pattern))), nil)
+
+	defer iter.Release()
+
+	var keys []string
+	now := time.Now()
+
+	for iter.Next() {
+		key := string(iter.Key())
+
+		if !internal(iter.Key()) && driver.Match(pattern, key) && !expired(expires, key, now) {
+			keys = append(keys, key)
+		}
+	}
+
+	if err := iter.Error(); err != nil {
+		return nil, wrapError(op, "", err)
+	}
+
+	return keys, nil
+}
+
+// KeysMatch returns the keys that matches the pattern.
+func (s *Driver) KeysMatch(pattern string) ([]string, error) {
+	return s.match("keys", pattern)
+}
+
+// CountMatch returns the number of keys that matches the pattern.
+func (s *Driver) CountMatch(pattern string) (int64, error) {
+	keys, err := s.match("count", pattern)
+
+	return int64(len(keys)), err
+}
@@ -0,0 +1,70 @@
+package leveldb
+
+import (
+	"time"
+
+	"github.com/frozzare/go-store/driver"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+// Range returns up to limit keys and values where start <= key < end
+// using a LevelDB iterator.
+func (s *Driver) Range(start, end string, limit int, reverse bool) ([]driver.KV, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	db, err := s.db()
+
+	if err != nil {
+		return nil, wrapError("range", "", err)
+	}
+
+	expires, err := s.expires(db)
+
+	if err != nil {
+		return nil, wrapError("range", "", err)
+	}
+
+	r := &util.Range{Start: []byte(start)}
+	if end != "" {
+		r.Limit = []byte(end)
+	}
+
+	iter := db.NewIterator(r, nil)
+
+	defer iter.Release()
+
+	ok, next := iter.First(), iter.Next
+	if reverse {
+		ok, next = iter.Last(), iter.Prev
+	}
+
+	var pairs []driver.KV
+	now := time.Now()
+
+	for ; ok; ok = next() {
+		key := string(iter.Key())
+
+		if internal(iter.Key()) || expired(expires, key, now) {
+			continue
+		}
+
+		value, err := driver.Decode(s.codec, iter.Value(), nil)
+
+		if err != nil {
+			return nil, wrapError("range", key, err)
+		}
+
+		pairs = append(pairs, driver.KV{Key: key, Value: value})
+
+		if limit > 0 && len(pairs) == limit {
+			break
+		}
+	}
+
+	if err := iter.Error(); err != nil {
+		return nil, wrapError("range", "", err)
+	}
+
+	return pairs, nil
+}
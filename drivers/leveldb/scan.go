@@ -0,0 +1,62 @@
+package leveldb
+
+import (
+	"time"
+
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+// Scan returns up to limit keys with the prefix starting at the cursor.
+// The keys is sorted, so the cursor is the last key that was returned.
+func (s *Driver) Scan(prefix, cursor string, limit int) ([]string, string, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	db, err := s.db()
+
+	if err != nil {
+		return nil, "", wrapError("scan", "", err)
+	}
+
+	iter := db.NewIterator(util.BytesPrefix([]byte(prefix)), nil)
+
+	defer iter.Release()
+
+	var keys []string
+	var next string
+	now := time.Now()
+
+	ok := iter.First()
+	if cursor > prefix {
+		ok = iter.Seek([]byte(cursor))
+	}
+
+	for ; ok; ok = iter.Next() {
+		key := string(iter.Key())
+
+		if key == cursor || internal(iter.Key()) {
+			continue
+		}
+
+		if expired, err := s.expired(db, key, now); err != nil {
+			return nil, "", wrapError("scan", "", err)
+		} else if expired {
+			continue
+		}
+
+		// One more key than the limit is read to know
+		// if there is more keys left.
+		if limit > 0 && len(keys) == limit {
+			next = keys[limit-1]
+			break
+		}
+
+		keys = append(keys, key)
+	}
+
+	if err := iter.Error(); err != nil {
+		return nil, "", wrapError("scan", "", err)
+	}
+
+	return keys, next, nil
+}
@@ -0,0 +1,55 @@
+package leveldb
+
+import (
+	"github.com/frozzare/go-store/driver"
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+// setIf sets key to value if the existence of the key
+// is the same as present while holding the driver lock.
+func (s *Driver) setIf(key string, value interface{}, present bool) (bool, error) {
+	data, err := s.codec.Marshal(value)
+
+	if err != nil {
+		return false, wrapError("set", key, err)
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	db, err := s.db()
+
+	if err != nil {
+		return false, wrapError("set", key, err)
+	}
+
+	exists, err := s.exists(db, key)
+
+	if err != nil {
+		return false, wrapError("set", key, err)
+	}
+
+	if exists != present {
+		return false, nil
+	}
+
+	batch := new(leveldb.Batch)
+	batch.Put([]byte(key), data)
+	batch.Delete(ttlKey(key))
+
+	if err := s.write(db, batch, driver.EventSet, key); err != nil {
+		return false, wrapError("set", key, err)
+	}
+
+	return true, nil
+}
+
+// SetIfAbsent sets key to value if the key does not exist.
+func (s *Driver) SetIfAbsent(key string, value interface{}) (bool, error) {
+	return s.setIf(key, value, false)
+}
+
+// SetIfPresent sets key to value if the key exist.
+func (s *Driver) SetIfPresent(key string, value interface{}) (bool, error) {
+	return s.setIf(key, value, true)
+}
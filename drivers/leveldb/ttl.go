@@ -0,0 +1,265 @@
+package leveldb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"time"
+
+	"github.com/frozzare/go-store/driver"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+// reserved is the prefix of the keys the driver stores next to the
+// values, such as the expiration times and versions. Keys with the
+// prefix is reserved and left out when the keys is listed.
+const reserved = "\x00store:"
+
+// ttlPrefix is the prefix for the keys that holds the expiration
+// time for keys with a ttl.
+const ttlPrefix = reserved + "ttl:"
+
+// ttlKey returns the key that holds the expiration time for a key.
+func ttlKey(key string) []byte {
+	return []byte(ttlPrefix + key)
+}
+
+// internal reports if the key is one of the reserved keys.
+func internal(key []byte) bool {
+	return bytes.HasPrefix(key, []byte(reserved))
+}
+
+// decodeTime decodes a stored expiration time.
+func decodeTime(value []byte) time.Time {
+	return time.Unix(0, int64(binary.BigEndian.Uint64(value)))
+}
+
+// encodeTime encodes a expiration time.
+func encodeTime(t time.Time) []byte {
+	value := make([]byte, 8)
+	binary.BigEndian.PutUint64(value, uint64(t.UnixNano()))
+	return value
+}
+
+// expires returns the expiration times for all keys with a ttl.
+func (s *Driver) expires(db *leveldb.DB) (map[string]time.Time, error) {
+	expires := make(map[string]time.Time)
+	iter := db.NewIterator(util.BytesPrefix([]byte(ttlPrefix)), nil)
+
+	for iter.Next() {
+		expires[string(iter.Key()[len(ttlPrefix):])] = decodeTime(iter.Value())
+	}
+
+	iter.Release()
+
+	return expires, iter.Error()
+}
+
+// expired reports if the key has expired in the given expiration times.
+func expired(expires map[string]time.Time, key string, now time.Time) bool {
+	t, ok := expires[key]
+
+	return ok && !now.Before(t)
+}
+
+// reader is implemented by both leveldb.DB and leveldb.Transaction.
+type reader interface {
+	Get(key []byte, ro *opt.ReadOptions) ([]byte, error)
+	Has(key []byte, ro *opt.ReadOptions) (bool, error)
+}
+
+// expired reports if the key has expired.
+func (s *Driver) expired(db reader, key string, now time.Time) (bool, error) {
+	value, err := db.Get(ttlKey(key), nil)
+
+	if err == leveldb.ErrNotFound {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	return !now.Before(decodeTime(value)), nil
+}
+
+// exists reports if a key exists and has not expired.
+func (s *Driver) exists(db reader, key string) (bool, error) {
+	exists, err := db.Has([]byte(key), nil)
+
+	if err != nil || !exists {
+		return false, err
+	}
+
+	expired, err := s.expired(db, key, time.Now())
+
+	return !expired, err
+}
+
+// purge removes expired keys and reports if there is keys left with
+// a ttl. Nothing is left once the database is closed, so the janitor
+// stops if the database can't be opened.
+func (s *Driver) purge() bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	db, err := s.db()
+
+	if err != nil {
+		return false
+	}
+
+	expires, err := s.expires(db)
+
+	if err != nil {
+		return true
+	}
+
+	var keys []string
+
+	batch := new(leveldb.Batch)
+	now := time.Now()
+
+	for key := range expires {
+		if expired(expires, key, now) {
+			batch.Delete([]byte(key))
+			batch.Delete(ttlKey(key))
+			delete(expires, key)
+			keys = append(keys, key)
+		}
+	}
+
+	if err := s.write(db, batch, driver.EventExpire, keys...); err != nil {
+		return true
+	}
+
+	return len(expires) > 0
+}
+
+// SetWithTTL key with value in store that expires after the ttl.
+func (s *Driver) SetWithTTL(key string, value interface{}, ttl time.Duration) error {
+	if ttl <= 0 {
+		return wrapError("set", key, driver.ErrInvalidArgs)
+	}
+
+	data, err := s.codec.Marshal(value)
+
+	if err != nil {
+		return wrapError("set", key, err)
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	db, err := s.db()
+
+	if err != nil {
+		return wrapError("set", key, err)
+	}
+
+	batch := new(leveldb.Batch)
+	batch.Put([]byte(key), data)
+	batch.Put(ttlKey(key), encodeTime(time.Now().Add(ttl)))
+
+	if err := s.write(db, batch, driver.EventSet, key); err != nil {
+		return wrapError("set", key, err)
+	}
+
+	s.janitor.Start()
+
+	return nil
+}
+
+// TTL returns the time left before the key expires
+// or NoExpiration if the key does not expire.
+func (s *Driver) TTL(key string) (time.Duration, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	db, err := s.db()
+
+	if err != nil {
+		return 0, wrapError("ttl", key, err)
+	}
+
+	exists, err := s.exists(db, key)
+
+	if err != nil {
+		return 0, wrapError("ttl", key, err)
+	}
+
+	if !exists {
+		return 0, wrapError("ttl", key, driver.ErrNotFound)
+	}
+
+	value, err := db.Get(ttlKey(key), nil)
+
+	if err == leveldb.ErrNotFound {
+		return driver.NoExpiration, nil
+	}
+
+	if err != nil {
+		return 0, wrapError("ttl", key, err)
+	}
+
+	return time.Until(decodeTime(value)), nil
+}
+
+// Expire sets the ttl for a existing key.
+func (s *Driver) Expire(key string, ttl time.Duration) error {
+	if ttl <= 0 {
+		return wrapError("expire", key, driver.ErrInvalidArgs)
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	db, err := s.db()
+
+	if err != nil {
+		return wrapError("expire", key, err)
+	}
+
+	exists, err := s.exists(db, key)
+
+	if err != nil {
+		return wrapError("expire", key, err)
+	}
+
+	if !exists {
+		return wrapError("expire", key, driver.ErrNotFound)
+	}
+
+	if err := db.Put(ttlKey(key), encodeTime(time.Now().Add(ttl)), nil); err != nil {
+		return wrapError("expire", key, err)
+	}
+
+	s.janitor.Start()
+
+	return nil
+}
+
+// Persist removes the expiration from a existing key.
+func (s *Driver) Persist(key string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	db, err := s.db()
+
+	if err != nil {
+		return wrapError("persist", key, err)
+	}
+
+	exists, err := s.exists(db, key)
+
+	if err != nil {
+		return wrapError("persist", key, err)
+	}
+
+	if !exists {
+		return wrapError("persist", key, driver.ErrNotFound)
+	}
+
+	return wrapError("persist", key, db.Delete(ttlKey(key), nil))
+}
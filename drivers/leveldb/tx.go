@@ -0,0 +1,134 @@
+package leveldb
+
+import (
+	"time"
+
+	"github.com/frozzare/go-store/driver"
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+// tx represents a LevelDB transaction.
+type tx struct {
+	s      *Driver
+	tx     *leveldb.Transaction
+	events []driver.Event
+}
+
+// Exists returns true when a key exists false when not existing in store.
+func (t *tx) Exists(key string) (bool, error) {
+	exists, err := t.s.exists(t.tx, key)
+
+	if err != nil {
+		return false, wrapError("exists", key, err)
+	}
+
+	return exists, nil
+}
+
+// Get returns the value for a key if any.
+func (t *tx) Get(key string, args ...interface{}) (interface{}, error) {
+	if err := driver.CheckArgs(args); err != nil {
+		return nil, wrapError("get", key, err)
+	}
+
+	res, err := t.tx.Get([]byte(key), nil)
+
+	if err != nil {
+		return nil, wrapError("get", key, err)
+	}
+
+	if expired, err := t.s.expired(t.tx, key, time.Now()); err != nil || expired {
+		if err == nil {
+			err = driver.ErrNotFound
+		}
+
+		return nil, wrapError("get", key, err)
+	}
+
+	value, err := driver.Decode(t.s.codec, res, args)
+
+	if err != nil {
+		return nil, wrapError("get", key, err)
+	}
+
+	return value, nil
+}
+
+// Set key with value in store.
+func (t *tx) Set(key string, value interface{}) error {
+	data, err := t.s.codec.Marshal(value)
+
+	if err != nil {
+		return wrapError("set", key, err)
+	}
+
+	batch := new(leveldb.Batch)
+	batch.Put([]byte(key), data)
+	batch.Delete(ttlKey(key))
+
+	if err := bump(t.tx, batch, key); err != nil {
+		return wrapError("set", key, err)
+	}
+
+	if err := t.tx.Write(batch, nil); err != nil {
+		return wrapError("set", key, err)
+	}
+
+	t.events = append(t.events, driver.Event{Type: driver.EventSet, Key: key})
+
+	return nil
+}
+
+// Delete key from store.
+func (t *tx) Delete(key string) error {
+	batch := new(leveldb.Batch)
+	batch.Delete([]byte(key))
+	batch.Delete(ttlKey(key))
+	unversion(batch, key)
+
+	if err := t.tx.Write(batch, nil); err != nil {
+		return wrapError("delete", key, err)
+	}
+
+	t.events = append(t.events, driver.Event{Type: driver.EventDelete, Key: key})
+
+	return nil
+}
+
+// Update runs fn in a LevelDB transaction. The changes is
+// committed if fn returns nil and discarded if not.
+func (s *Driver) Update(fn func(tx driver.Tx) error) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	db, err := s.db()
+
+	if err != nil {
+		return wrapError("update", "", err)
+	}
+
+	tr, err := db.OpenTransaction()
+
+	if err != nil {
+		return wrapError("update", "", err)
+	}
+
+	// Discard does nothing after the transaction is committed.
+	defer tr.Discard()
+
+	t := &tx{s: s, tx: tr}
+
+	if err := fn(t); err != nil {
+		return err
+	}
+
+	if err := tr.Commit(); err != nil {
+		return wrapError("update", "", err)
+	}
+
+	for _, e := range t.events {
+		s.hub.Publish(e.Type, e.Key)
+	}
+
+	return nil
+}
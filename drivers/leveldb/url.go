@@ -0,0 +1,35 @@
+package leveldb
+
+import (
+	"net/url"
+
+	"github.com/frozzare/go-store/driver"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+)
+
+// init registers the leveldb:// url scheme.
+func init() {
+	driver.RegisterURL("leveldb", openURL)
+}
+
+// openURL opens a store from a leveldb:///path/to/db url. The query
+// parameters is codec and read_only.
+func openURL(u *url.URL) (driver.Driver, error) {
+	if err := driver.URLParams(u, "codec", "read_only"); err != nil {
+		return nil, err
+	}
+
+	codec, err := driver.URLCodec(u)
+
+	if err != nil {
+		return nil, err
+	}
+
+	readOnly, err := driver.URLBool(u, "read_only")
+
+	if err != nil {
+		return nil, err
+	}
+
+	return Open(codec, WithPath(driver.URLPath(u)), WithOptions(&opt.Options{ReadOnly: readOnly}))
+}
@@ -0,0 +1,38 @@
+package leveldb
+
+import (
+	"context"
+
+	"github.com/frozzare/go-store/driver"
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+// write applies the batch and sends the events to the watchers if the
+// batch was written. The keys that is set gets a new version and the
+// versions of the keys that is deleted is removed in the same batch.
+func (s *Driver) write(db *leveldb.DB, batch *leveldb.Batch, typ driver.EventType, keys ...string) error {
+	switch typ {
+	case driver.EventSet:
+		if err := bump(db, batch, keys...); err != nil {
+			return err
+		}
+	case driver.EventDelete, driver.EventExpire:
+		unversion(batch, keys...)
+	}
+
+	if err := db.Write(batch, nil); err != nil {
+		return err
+	}
+
+	s.hub.Publish(typ, keys...)
+
+	return nil
+}
+
+// Watch returns a channel that receives events for keys with the
+// prefix until the context is done. Only the changes made through
+// this driver is sent, and Expire events is sent when the janitor
+// removes the expired keys.
+func (s *Driver) Watch(ctx context.Context, prefix string) (<-chan driver.Event, error) {
+	return s.hub.Watch(ctx, prefix), nil
+}
@@ -0,0 +1,118 @@
+package redis
+
+import (
+	"github.com/frozzare/go-store/driver"
+	"gopkg.in/redis.v5"
+)
+
+// GetMulti returns the values for the keys that exists in store.
+func (s *Driver) GetMulti(keys []string, args ...interface{}) (map[string]interface{}, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if s.closed {
+		return nil, wrapError("getmulti", "", driver.ErrClosed)
+	}
+
+	if err := driver.CheckArgs(args); err != nil {
+		return nil, wrapError("getmulti", "", err)
+	}
+
+	found := make(map[string]string, len(keys))
+
+	if len(keys) > 0 {
+		res, err := s.mget(s.keys(keys))
+
+		if err != nil {
+			return nil, wrapError("getmulti", "", err)
+		}
+
+		for i, value := range res {
+			if str, ok := value.(string); ok {
+				found[keys[i]] = str
+			}
+		}
+	}
+
+	values, err := driver.DecodeMulti(keys, args, func(key string, v interface{}) error {
+		str, ok := found[key]
+
+		if !ok {
+			return driver.ErrNotFound
+		}
+
+		return driver.Unmarshal(s.codec, []byte(str), v)
+	})
+
+	if err != nil {
+		return nil, wrapError("getmulti", "", err)
+	}
+
+	return values, nil
+}
+
+// SetMulti keys with values in store. The keys is set one by one
+// in the transaction, so they can be in different cluster slots.
+func (s *Driver) SetMulti(values map[string]interface{}) error {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if s.closed {
+		return wrapError("setmulti", "", driver.ErrClosed)
+	}
+
+	if len(values) == 0 {
+		return nil
+	}
+
+	data := make(map[string][]byte, len(values))
+	keys := make([]string, 0, len(values))
+
+	for key, value := range values {
+		b, err := s.codec.Marshal(value)
+
+		if err != nil {
+			return wrapError("setmulti", key, err)
+		}
+
+		data[key] = b
+		keys = append(keys, key)
+	}
+
+	err := s.pipelined(func(pipe *redis.Pipeline) error {
+		for key, b := range data {
+			pipe.Set(s.key(key), b, 0)
+			bump(pipe, 0, s.key(key))
+		}
+
+		return s.reindex(pipe, keys, nil)
+	})
+
+	return wrapError("setmulti", "", err)
+}
+
+// DeleteMulti keys from store. The keys is deleted one by one
+// in the transaction, so they can be in different cluster slots.
+func (s *Driver) DeleteMulti(keys ...string) error {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if s.closed {
+		return wrapError("deletemulti", "", driver.ErrClosed)
+	}
+
+	if len(keys) == 0 {
+		return nil
+	}
+
+	err := s.pipelined(func(pipe *redis.Pipeline) error {
+		for _, key := range keys {
+			pipe.Del(s.key(key))
+			unversion(pipe, s.key(key))
+		}
+
+		return s.reindex(pipe, nil, keys)
+	})
+
+	return wrapError("deletemulti", "", err)
+}
@@ -0,0 +1,180 @@
+package redis
+
+import (
+	"strings"
+	"time"
+
+	"github.com/frozzare/go-store/driver"
+	"gopkg.in/redis.v5"
+)
+
+// keepTTL is given to bump for writes that keeps the ttl of the key.
+const keepTTL = time.Duration(-1)
+
+// versioner is the commands the version of a key is read
+// with, implemented by both the clients and transactions.
+type versioner interface {
+	Exists(key string) *redis.BoolCmd
+	Get(key string) *redis.StringCmd
+}
+
+// hasTag reports if the key has a hash tag, which Redis
+// hashes instead of the whole key to find the slot.
+func hasTag(key string) bool {
+	if i := strings.IndexByte(key, '{'); i > -1 {
+		return strings.IndexByte(key[i+1:], '}') > 0
+	}
+
+	return false
+}
+
+// versionKey returns the companion key that holds the version of
+// the key with the prefix. It has the hash tag of the key or the key
+// itself as hash tag, so it's in the same cluster slot and ring shard
+// as the key. A key that contains } but has no hash tag can't be given
+// a companion in the same slot, so it can't be compared and set in a
+// cluster.
+func versionKey(k string) string {
+	if hasTag(k) {
+		return versionPrefix + k
+	}
+
+	return versionPrefix + "{" + k + "}"
+}
+
+// seed returns the first version of a key, a version is taken from
+// the clock when the companion key is created so a key that is deleted
+// or expired and written again does not get back a earlier version.
+func seed() int64 {
+	return time.Now().UnixNano()
+}
+
+// bump increments the versions of the keys with the prefix in the
+// pipeline. A ttl is given to the versions so they expires with the
+// keys, 0 removes the ttl and keepTTL keeps it.
+func bump(pipe *redis.Pipeline, ttl time.Duration, keys ...string) {
+	for _, k := range keys {
+		vk := versionKey(k)
+		pipe.SetNX(vk, seed(), 0)
+		pipe.Incr(vk)
+
+		switch {
+		case ttl > 0:
+			pipe.PExpire(vk, ttl)
+		case ttl == 0:
+			pipe.Persist(vk)
+		}
+	}
+}
+
+// unversion removes the versions of the deleted keys with the prefix
+// in the pipeline. The keys is deleted one by one, since they can be
+// in different cluster slots.
+func unversion(pipe *redis.Pipeline, keys ...string) {
+	for _, k := range keys {
+		pipe.Del(versionKey(k))
+	}
+}
+
+// versionOf returns the version of the key with the prefix or a empty
+// string if the key does not exist. A key written before the versions
+// was stored or by another client than the driver has the version 0
+// until it's written again.
+func versionOf(c versioner, k string) (string, error) {
+	if ok, err := c.Exists(k).Result(); err != nil || !ok {
+		return "", err
+	}
+
+	v, err := c.Get(versionKey(k)).Result()
+
+	if err == redis.Nil {
+		return "0", nil
+	}
+
+	return v, err
+}
+
+// GetWithVersion returns the value and version for a key. The version
+// is read before the value, so a write in between gives a version that
+// is older than the value and a conflict instead of a lost write.
+func (s *Driver) GetWithVersion(key string, args ...interface{}) (interface{}, string, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if s.closed {
+		return nil, "", wrapError("get", key, driver.ErrClosed)
+	}
+
+	if err := driver.CheckArgs(args); err != nil {
+		return nil, "", wrapError("get", key, err)
+	}
+
+	var ver, res *redis.StringCmd
+
+	_, err := s.client.Pipelined(func(pipe *redis.Pipeline) error {
+		ver = pipe.Get(versionKey(s.key(key)))
+		res = pipe.Get(s.key(key))
+		return nil
+	})
+
+	if err != nil && err != redis.Nil {
+		return nil, "", wrapError("get", key, err)
+	}
+
+	data, err := res.Bytes()
+
+	if err != nil {
+		return nil, "", wrapError("get", key, err)
+	}
+
+	version := ver.Val()
+
+	if ver.Err() == redis.Nil {
+		version = "0"
+	}
+
+	value, err := driver.Decode(s.codec, data, args)
+
+	if err != nil {
+		return nil, "", wrapError("get", key, err)
+	}
+
+	return value, version, nil
+}
+
+// CompareAndSet sets key to value if the version of the key is the
+// given version. The key is watched while the version is checked,
+// so ErrConflict is returned if it's changed before the write.
+func (s *Driver) CompareAndSet(key, version string, value interface{}) error {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if s.closed {
+		return wrapError("cas", key, driver.ErrClosed)
+	}
+
+	data, err := s.codec.Marshal(value)
+
+	if err != nil {
+		return wrapError("cas", key, err)
+	}
+
+	k := s.key(key)
+
+	return wrapError("cas", key, s.watch(func(tx *redis.Tx) error {
+		current, err := versionOf(tx, k)
+
+		if err != nil {
+			return err
+		}
+
+		if current != version {
+			return driver.ErrConflict
+		}
+
+		return s.commit(tx, func(pipe *redis.Pipeline) {
+			pipe.Set(k, data, 0)
+			bump(pipe, 0, k)
+		}, []string{key}, nil)
+	}, k))
+}
@@ -0,0 +1,292 @@
+package redis
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/frozzare/go-store/driver"
+	"gopkg.in/redis.v5"
+)
+
+// Client is the common interface of the Redis clients the driver
+// works with: *redis.Client, which redis.NewFailoverClient also
+// returns for Sentinel, *redis.ClusterClient and *redis.Ring.
+type Client interface {
+	redis.Cmdable
+
+	Process(cmd redis.Cmder) error
+	Publish(channel, message string) *redis.IntCmd
+	Close() error
+}
+
+// txPipeliner is implemented by the clients that
+// can send a pipeline in a MULTI/EXEC block.
+type txPipeliner interface {
+	TxPipelined(fn func(*redis.Pipeline) error) ([]redis.Cmder, error)
+}
+
+// watcher is implemented by the clients that has
+// optimistic transactions with WATCH.
+type watcher interface {
+	Watch(fn func(*redis.Tx) error, keys ...string) error
+}
+
+// indexer is the commands the key index is updated with,
+// implemented by both the clients and pipelines.
+type indexer interface {
+	ZAdd(key string, members ...redis.Z) *redis.IntCmd
+	ZRem(key string, members ...interface{}) *redis.IntCmd
+}
+
+// newClient returns the client for the config. Client is used if set,
+// otherwise a client is created from the first options that is set of
+// ClusterOptions, FailoverOptions, RingOptions and Options.
+func newClient(c Config) Client {
+	switch {
+	case c.Client != nil:
+		return c.Client
+	case c.ClusterOptions != nil:
+		return redis.NewClusterClient(c.ClusterOptions)
+	case c.FailoverOptions != nil:
+		return redis.NewFailoverClient(c.FailoverOptions)
+	case c.RingOptions != nil:
+		return redis.NewRing(c.RingOptions)
+	default:
+		return redis.NewClient(c.Options)
+	}
+}
+
+// database returns the database index of the client newClient creates
+// for the config. A cluster or ring only has database 0, and so is a
+// Client from the config assumed to use, since a v5 client does not
+// expose its options.
+func database(c Config) int {
+	switch {
+	case c.Client != nil, c.ClusterOptions != nil:
+		return 0
+	case c.FailoverOptions != nil:
+		return c.FailoverOptions.DB
+	case c.RingOptions != nil:
+		return 0
+	default:
+		return c.Options.DB
+	}
+}
+
+// sharded reports if the keys is spread over several servers, so a
+// command or transaction can't span keys in different slots or the
+// keys and the key index.
+func (s *Driver) sharded() bool {
+	switch s.client.(type) {
+	case *redis.ClusterClient, *redis.Ring:
+		return true
+	}
+
+	return false
+}
+
+// forEachMaster calls fn with the client of every master in a
+// cluster or shard in a ring concurrently, a single server is
+// called with the client itself.
+func (s *Driver) forEachMaster(fn func(c Client) error) error {
+	switch c := s.client.(type) {
+	case *redis.ClusterClient:
+		return c.ForEachMaster(func(c *redis.Client) error {
+			return fn(c)
+		})
+	case *redis.Ring:
+		return c.ForEachShard(func(c *redis.Client) error {
+			return fn(c)
+		})
+	}
+
+	return fn(s.client)
+}
+
+// masters returns the client of every master in a cluster or shard
+// in a ring sorted by address and database, so the order is the same between calls
+// as long as the masters does not change. A single server returns
+// the client itself.
+func (s *Driver) masters() ([]Client, error) {
+	if !s.sharded() {
+		return []Client{s.client}, nil
+	}
+
+	var mu sync.Mutex
+	var clients []*redis.Client
+
+	err := s.forEachMaster(func(c Client) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		clients = append(clients, c.(*redis.Client))
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(clients, func(i, j int) bool {
+		return clients[i].String() < clients[j].String()
+	})
+
+	res := make([]Client, len(clients))
+
+	for i, c := range clients {
+		res[i] = c
+	}
+
+	return res, nil
+}
+
+// pipelined runs fn in a MULTI/EXEC transaction on a single server.
+// On a cluster or ring the keys and the key index can be on different
+// servers, so fn is run in a pipeline that is not atomic.
+func (s *Driver) pipelined(fn func(pipe *redis.Pipeline) error) error {
+	if t, ok := s.client.(txPipeliner); ok && !s.sharded() {
+		_, err := t.TxPipelined(fn)
+		return err
+	}
+
+	_, err := s.client.Pipelined(fn)
+
+	return err
+}
+
+// watch runs fn in a optimistic transaction that watches the keys,
+// ErrNotSupported is returned if the client has no transactions.
+func (s *Driver) watch(fn func(tx *redis.Tx) error, keys ...string) error {
+	w, ok := s.client.(watcher)
+
+	if !ok {
+		return driver.ErrNotSupported
+	}
+
+	return w.Watch(fn, keys...)
+}
+
+// reindex adds the set keys to and removes the deleted keys from
+// the key index with c, nothing is done if the index is not kept.
+func (s *Driver) reindex(c indexer, set, deleted []string) error {
+	if !s.indexed {
+		return nil
+	}
+
+	if len(set) > 0 {
+		if err := c.ZAdd(s.index(), members(set...)...).Err(); err != nil {
+			return err
+		}
+	}
+
+	if len(deleted) > 0 {
+		z := make([]interface{}, len(deleted))
+
+		for i, key := range deleted {
+			z[i] = key
+		}
+
+		if err := c.ZRem(s.index(), z...).Err(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// commit runs fn in the MULTI/EXEC block of the transaction and
+// updates the key index with the set and deleted keys. The key index
+// is on another server than the keys in a cluster or ring, so it's
+// updated after the transaction is committed there.
+func (s *Driver) commit(tx *redis.Tx, fn func(pipe *redis.Pipeline), set, deleted []string) error {
+	_, err := tx.Pipelined(func(pipe *redis.Pipeline) error {
+		fn(pipe)
+
+		if s.sharded() {
+			return nil
+		}
+
+		return s.reindex(pipe, set, deleted)
+	})
+
+	if err != nil || !s.sharded() {
+		return err
+	}
+
+	return s.reindex(s.client, set, deleted)
+}
+
+// mget returns the values of the keys, where missing keys is nil,
+// with MGET on a single server. The keys can be in different slots
+// in a cluster or ring, so they is read with GET in a pipeline there.
+func (s *Driver) mget(keys []string) ([]interface{}, error) {
+	if !s.sharded() {
+		return s.client.MGet(keys...).Result()
+	}
+
+	cmds := make([]*redis.StringCmd, len(keys))
+
+	_, err := s.client.Pipelined(func(pipe *redis.Pipeline) error {
+		for i, key := range keys {
+			cmds[i] = pipe.Get(key)
+		}
+
+		return nil
+	})
+
+	if err != nil && err != redis.Nil {
+		return nil, err
+	}
+
+	values := make([]interface{}, len(keys))
+
+	for i, cmd := range cmds {
+		if v, err := cmd.Result(); err == nil {
+			values[i] = v
+		}
+	}
+
+	return values, nil
+}
+
+// scan calls fn with each page of the keys with the prefix that
+// matches the pattern using SCAN MATCH on every master. The keys has
+// the prefix and the same key can be in more than one page.
+func (s *Driver) scan(pattern string, fn func(c Client, keys []string) error) error {
+	return s.scanMatch(escape(s.prefix)+pattern, fn)
+}
+
+// scanMatch calls fn with each page of the keys that matches the
+// pattern using SCAN MATCH on every master. The masters is scanned
+// concurrently but fn is called by one at a time with the client of
+// the master the keys is on.
+func (s *Driver) scanMatch(match string, fn func(c Client, keys []string) error) error {
+	var mu sync.Mutex
+
+	return s.forEachMaster(func(c Client) error {
+		var cursor uint64
+
+		for {
+			res, next, err := c.Scan(cursor, match, batchSize).Result()
+
+			if err != nil {
+				return err
+			}
+
+			if len(res) > 0 {
+				mu.Lock()
+				err = fn(c, res)
+				mu.Unlock()
+
+				if err != nil {
+					return err
+				}
+			}
+
+			if cursor = next; cursor == 0 {
+				return nil
+			}
+		}
+	})
+}
@@ -0,0 +1,348 @@
+package redis
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/frozzare/go-assert"
+	"github.com/frozzare/go-store/driver"
+	"github.com/frozzare/go-store/storetest"
+	"gopkg.in/redis.v5"
+)
+
+// server starts a redis-server process on the port with the args and
+// kills it when the test is done. The test is skipped if redis-server
+// is not installed.
+func server(t *testing.T, port int, args ...string) string {
+	t.Helper()
+
+	path, err := exec.LookPath("redis-server")
+
+	if err != nil {
+		t.Skip("redis-server is not installed")
+	}
+
+	dir := t.TempDir()
+	args = append(args, "--port", strconv.Itoa(port), "--dir", dir, "--save", "", "--appendonly", "no")
+	cmd := exec.Command(path, args...)
+
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("start redis-server: %v", err)
+	}
+
+	t.Cleanup(func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	})
+
+	addr := fmt.Sprintf("127.0.0.1:%d", port)
+	client := redis.NewClient(&redis.Options{Addr: addr})
+	defer client.Close()
+
+	for i := 0; i < 50; i++ {
+		if client.Ping().Err() == nil {
+			return addr
+		}
+
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	t.Fatalf("redis-server on %s did not start", addr)
+
+	return ""
+}
+
+// sentinel starts a redis-server in sentinel mode on the port that
+// monitors the master as mymaster.
+func sentinel(t *testing.T, port int, master string) string {
+	t.Helper()
+
+	host, masterPort, _ := net.SplitHostPort(master)
+	config := fmt.Sprintf("sentinel monitor mymaster %s %s 1\n", host, masterPort)
+
+	// Sentinel rewrites its config file, so it must be writable.
+	file := filepath.Join(t.TempDir(), "sentinel.conf")
+
+	if err := ioutil.WriteFile(file, []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	return server(t, port, file, "--sentinel")
+}
+
+// cluster starts a redis-server cluster with a master on each port,
+// where every node meets the first one, and waits for the slots to
+// be assigned.
+func cluster(t *testing.T, ports ...int) []string {
+	t.Helper()
+
+	addrs := make([]string, len(ports))
+
+	for i, port := range ports {
+		addrs[i] = server(t, port, "--cluster-enabled", "yes", "--cluster-config-file", "nodes.conf")
+	}
+
+	const slots = 16384
+
+	host, port, _ := net.SplitHostPort(addrs[0])
+
+	for i, addr := range addrs {
+		client := redis.NewClient(&redis.Options{Addr: addr})
+		defer client.Close()
+
+		min := i * slots / len(addrs)
+		max := (i+1)*slots/len(addrs) - 1
+
+		if err := client.ClusterAddSlotsRange(min, max).Err(); err != nil {
+			t.Fatalf("assign slots to %s: %v", addr, err)
+		}
+
+		if i > 0 {
+			if err := client.ClusterMeet(host, port).Err(); err != nil {
+				t.Fatalf("meet %s from %s: %v", addrs[0], addr, err)
+			}
+		}
+	}
+
+	for _, addr := range addrs {
+		client := redis.NewClient(&redis.Options{Addr: addr})
+		defer client.Close()
+
+		for i := 0; ; i++ {
+			info, _ := client.ClusterInfo().Result()
+
+			if strings.Contains(info, "cluster_state:ok") {
+				break
+			}
+
+			if i == 100 {
+				t.Fatalf("cluster node %s is not ok: %s", addr, info)
+			}
+
+			time.Sleep(100 * time.Millisecond)
+		}
+	}
+
+	return addrs
+}
+
+// testSharded checks that the keys is spread over the masters
+// and that Keys, Count, Scan and Flush sees all of them.
+func testSharded(t *testing.T, d driver.Driver) {
+	defer d.Flush()
+
+	want := make([]string, 20)
+	values := make(map[string]interface{}, len(want))
+
+	for i := range want {
+		want[i] = fmt.Sprintf("key-%02d", i)
+		values[want[i]] = i
+	}
+
+	assert.Nil(t, d.(driver.BatchDriver).SetMulti(values))
+
+	var masters int32
+
+	// The masters is called concurrently.
+	d.(*Driver).forEachMaster(func(c Client) error {
+		if n, _ := c.DbSize().Result(); n > 0 {
+			atomic.AddInt32(&masters, 1)
+		}
+
+		return nil
+	})
+
+	assert.True(t, masters > 1)
+
+	keys, err := d.Keys()
+	assert.Nil(t, err)
+	sort.Strings(keys)
+	assert.Equal(t, want, keys)
+
+	count, _ := d.Count()
+	assert.Equal(t, int64(len(want)), count)
+
+	// The pages goes over every master.
+	seen := make(map[string]bool)
+	cursor := ""
+	pages := 0
+
+	for ; pages < 100; pages++ {
+		keys, next, err := d.(driver.ScanDriver).Scan("key-1", cursor, 2)
+		assert.Nil(t, err)
+
+		for _, key := range keys {
+			seen[key] = true
+		}
+
+		if cursor = next; cursor == "" {
+			break
+		}
+	}
+
+	assert.True(t, pages > 1)
+	assert.Equal(t, 10, len(seen))
+
+	var found map[string]int
+	_, err = d.(driver.BatchDriver).GetMulti([]string{"key-03", "missing"}, &found)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]int{"key-03": 3}, found)
+
+	kv, err := d.(driver.RangeDriver).Range("key-05", "key-08", 0, false)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(kv))
+
+	assert.Nil(t, d.Flush())
+
+	count, _ = d.Count()
+	assert.Equal(t, int64(0), count)
+}
+
+func TestParseCursor(t *testing.T) {
+	i, c, err := parseCursor("", 3)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, i)
+	assert.Equal(t, uint64(0), c)
+
+	i, c, err = parseCursor("12", 1)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, i)
+	assert.Equal(t, uint64(12), c)
+
+	i, c, err = parseCursor(formatCursor(2, 12, 3), 3)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, i)
+	assert.Equal(t, uint64(12), c)
+
+	for _, cursor := range []string{"12", "3:12", "-1:12", "a:12", "1:a"} {
+		_, _, err = parseCursor(cursor, 3)
+		assert.True(t, errors.Is(err, driver.ErrInvalidArgs))
+	}
+
+	_, _, err = parseCursor("1:12", 1)
+	assert.True(t, errors.Is(err, driver.ErrInvalidArgs))
+}
+
+func TestVersionKey(t *testing.T) {
+	assert.Equal(t, versionPrefix+"{name}", versionKey("name"))
+	assert.Equal(t, versionPrefix+"app:{user}:name", versionKey("app:{user}:name"))
+	assert.False(t, hasTag("a{}b"))
+	assert.True(t, hasTag("a{b}"))
+}
+
+func TestNewClient(t *testing.T) {
+	c, _ := newConfig([]interface{}{WithRingOptions(&redis.RingOptions{
+		Addrs: map[string]string{"a": "localhost:7100"},
+	})})
+
+	ring := newClient(c)
+	defer ring.Close()
+
+	_, ok := ring.(*redis.Ring)
+	assert.True(t, ok)
+
+	d := &Driver{client: ring}
+	assert.True(t, d.sharded())
+	assert.Equal(t, "ttl|batch|scan|match|counter|conditional|prefix", d.Capabilities().String())
+
+	c, _ = newConfig([]interface{}{&redis.FailoverOptions{MasterName: "mymaster"}})
+	assert.Equal(t, "mymaster", c.FailoverOptions.MasterName)
+
+	c, _ = newConfig([]interface{}{&redis.ClusterOptions{Addrs: []string{"localhost:7000"}}})
+	assert.Equal(t, 1, len(c.ClusterOptions.Addrs))
+
+	c, _ = newConfig([]interface{}{ring})
+	assert.Equal(t, ring, c.Client)
+}
+
+func TestDatabase(t *testing.T) {
+	c, _ := newConfig(nil)
+	assert.Equal(t, 0, database(c))
+
+	c, _ = newConfig([]interface{}{&redis.Options{Addr: "localhost:6379", DB: 2}})
+	assert.Equal(t, 2, database(c))
+
+	c, _ = newConfig([]interface{}{&redis.FailoverOptions{MasterName: "mymaster", DB: 3}})
+	assert.Equal(t, 3, database(c))
+
+	c, _ = newConfig([]interface{}{&redis.ClusterOptions{Addrs: []string{"localhost:7000"}}})
+	assert.Equal(t, 0, database(c))
+}
+
+func TestRing(t *testing.T) {
+	a := server(t, 7100)
+	b := server(t, 7101)
+
+	open := func() driver.Driver {
+		d, _ := Open(WithRingOptions(&redis.RingOptions{
+			Addrs: map[string]string{"a": a, "b": b},
+		}), WithIndex())
+		return d
+	}
+
+	storetest.Run(t, open)
+	testSharded(t, open())
+
+	err := open().(driver.TxDriver).Update(func(tx driver.Tx) error {
+		return nil
+	})
+	assert.True(t, errors.Is(err, driver.ErrNotSupported))
+}
+
+func TestCluster(t *testing.T) {
+	addrs := cluster(t, 7000, 7001, 7002)
+
+	open := func() driver.Driver {
+		d, _ := Open(WithClusterOptions(&redis.ClusterOptions{Addrs: addrs}), WithIndex())
+		return d
+	}
+
+	storetest.Run(t, open)
+	testSharded(t, open())
+
+	d := open()
+	defer d.Flush()
+
+	cas := d.(driver.CASDriver)
+	assert.Nil(t, d.Set("name", "Fredrik"))
+
+	_, version, err := cas.GetWithVersion("name")
+	assert.Nil(t, err)
+	assert.Nil(t, cas.CompareAndSet("name", version, "Elli"))
+
+	kv, _ := d.(driver.RangeDriver).Range("", "", 0, false)
+	assert.Equal(t, 1, len(kv))
+
+	assert.False(t, driver.CapabilitiesOf(d).Has(driver.CapTx))
+
+	err = d.(driver.TxDriver).Update(func(tx driver.Tx) error {
+		return tx.Set("name", "Fredrik")
+	})
+	assert.True(t, errors.Is(err, driver.ErrNotSupported))
+}
+
+func TestFailover(t *testing.T) {
+	master := server(t, 7200)
+	addr := sentinel(t, 27200, master)
+
+	open := func() driver.Driver {
+		d, _ := Open(WithFailoverOptions(&redis.FailoverOptions{
+			MasterName:    "mymaster",
+			SentinelAddrs: []string{addr},
+		}))
+		return d
+	}
+
+	storetest.Run(t, open)
+	assert.True(t, driver.CapabilitiesOf(open()).Has(driver.CapWatch|driver.CapTx))
+}
@@ -0,0 +1,202 @@
+package redis
+
+import (
+	"github.com/frozzare/go-store/codec"
+	"github.com/frozzare/go-store/driver"
+	"gopkg.in/redis.v5"
+)
+
+// Config is the configuration of a Redis store.
+type Config struct {
+	// Options is used to create the client, localhost:6379 by default.
+	// The Context methods can't abort a command, so use ReadTimeout
+	// and WriteTimeout to bound the commands themselves.
+	Options *redis.Options
+
+	// ClusterOptions creates a cluster client instead if set.
+	ClusterOptions *redis.ClusterOptions
+
+	// FailoverOptions creates a client for the master that
+	// Redis Sentinel reports instead if set.
+	FailoverOptions *redis.FailoverOptions
+
+	// RingOptions creates a ring client that shards the keys
+	// over several servers instead if set.
+	RingOptions *redis.RingOptions
+
+	// Client is used instead of creating one from the options if set.
+	// Watch assumes that it uses database 0.
+	Client Client
+
+	// Codec encodes the values, codec.JSON by default.
+	Codec driver.Codec
+
+	// Prefix is prepended to every key, so Count, Keys and Flush
+	// only sees the keys with the prefix, e.g. "app:".
+	Prefix string
+
+	// FlushMode is how Flush removes the keys, FlushScan by default.
+	FlushMode FlushMode
+
+	// Index keeps a sorted set of the keys, which Range needs since
+	// Redis keys has no order. Every write also updates the index, so
+	// it's off by default and Range returns ErrNotSupported. Expired
+	// keys is left in the index until Range finds them.
+	Index bool
+
+	// Notify enables the keyspace notifications Watch needs with
+	// CONFIG SET, which changes the config of the whole server. Watch
+	// returns ErrNotSupported if they is not enabled and Notify is not
+	// set.
+	Notify bool
+}
+
+// FlushMode is how Flush removes the keys.
+type FlushMode int
+
+const (
+	// FlushScan finds the keys with the prefix with SCAN MATCH and
+	// removes them in batches with UNLINK, other keys in the database
+	// is kept.
+	FlushScan FlushMode = iota
+
+	// FlushDB removes all keys in the database with FLUSHDB,
+	// including keys without the prefix.
+	FlushDB
+)
+
+// Option configures a Redis store.
+type Option func(*Config)
+
+// WithOptions sets the options the client is created with.
+func WithOptions(options *redis.Options) Option {
+	return func(c *Config) {
+		c.Options = options
+	}
+}
+
+// WithClusterOptions sets the options a cluster client is created with.
+func WithClusterOptions(options *redis.ClusterOptions) Option {
+	return func(c *Config) {
+		c.ClusterOptions = options
+	}
+}
+
+// WithFailoverOptions sets the options a Sentinel
+// backed failover client is created with.
+func WithFailoverOptions(options *redis.FailoverOptions) Option {
+	return func(c *Config) {
+		c.FailoverOptions = options
+	}
+}
+
+// WithRingOptions sets the options a ring client is created with.
+func WithRingOptions(options *redis.RingOptions) Option {
+	return func(c *Config) {
+		c.RingOptions = options
+	}
+}
+
+// WithClient sets a existing client to use, such as a
+// *redis.Client, *redis.ClusterClient or *redis.Ring.
+func WithClient(client Client) Option {
+	return func(c *Config) {
+		c.Client = client
+	}
+}
+
+// WithPrefix sets the prefix that is prepended to every key.
+func WithPrefix(prefix string) Option {
+	return func(c *Config) {
+		c.Prefix = prefix
+	}
+}
+
+// WithFlushMode sets how Flush removes the keys.
+func WithFlushMode(mode FlushMode) Option {
+	return func(c *Config) {
+		c.FlushMode = mode
+	}
+}
+
+// WithIndex keeps the key index that Range needs.
+func WithIndex() Option {
+	return func(c *Config) {
+		c.Index = true
+	}
+}
+
+// WithNotify lets Watch enable the keyspace notifications
+// on the server with CONFIG SET.
+func WithNotify() Option {
+	return func(c *Config) {
+		c.Notify = true
+	}
+}
+
+// newConfig returns the config for the Open args. The args is a
+// Config, Options, a driver.Codec or the positional *redis.Options,
+// *redis.ClusterOptions, *redis.FailoverOptions, *redis.RingOptions
+// or Client where a nil value keeps the default.
+func newConfig(args []interface{}) (Config, error) {
+	var c Config
+	var i int
+
+	for _, arg := range args {
+		switch v := arg.(type) {
+		case Option:
+			v(&c)
+			continue
+		case Config:
+			c = v
+			continue
+		case *Config:
+			if v != nil {
+				c = *v
+			}
+			continue
+		case driver.Codec:
+			c.Codec = v
+			continue
+		}
+
+		if arg != nil {
+			if i > 0 {
+				return c, driver.ArgError("redis", i, "a Option", arg)
+			}
+
+			switch v := arg.(type) {
+			case *redis.Options:
+				c.Options = v
+			case redis.Options:
+				c.Options = &v
+			case *redis.ClusterOptions:
+				c.ClusterOptions = v
+			case *redis.FailoverOptions:
+				c.FailoverOptions = v
+			case *redis.RingOptions:
+				c.RingOptions = v
+			case Client:
+				c.Client = v
+			default:
+				return c, driver.ArgError("redis", i, "a *redis.Options", arg)
+			}
+		}
+
+		i++
+	}
+
+	if c.Options == nil {
+		c.Options = &redis.Options{
+			Addr:     "localhost:6379",
+			Password: "",
+			DB:       0,
+		}
+	}
+
+	if c.Codec == nil {
+		c.Codec = codec.JSON{}
+	}
+
+	return c, nil
+}
@@ -0,0 +1,126 @@
+package redis
+
+import (
+	"github.com/frozzare/go-store/codec"
+	"github.com/frozzare/go-store/driver"
+	"gopkg.in/redis.v5"
+)
+
+// native reports if the codec encodes numbers as the plain decimal
+// strings that INCRBY and INCRBYFLOAT works on.
+func (s *Driver) native() bool {
+	_, ok := s.codec.(codec.JSON)
+	return ok
+}
+
+// modify replaces the data of a key with the data returned by fn.
+// The key is watched while fn runs and the ttl of the key is kept,
+// the write is retried if the key is changed before it's written.
+func (s *Driver) modify(op, key string, fn func(data []byte) ([]byte, error)) error {
+	k := s.key(key)
+
+	for {
+		err := s.watch(func(tx *redis.Tx) error {
+			data, err := tx.Get(k).Bytes()
+
+			if err == redis.Nil {
+				data, err = nil, nil
+			}
+
+			if err != nil {
+				return err
+			}
+
+			ttl, err := tx.PTTL(k).Result()
+
+			if err != nil {
+				return err
+			}
+
+			data, err = fn(data)
+
+			if err != nil {
+				return err
+			}
+
+			if ttl < 0 {
+				ttl = 0
+			}
+
+			return s.commit(tx, func(pipe *redis.Pipeline) {
+				pipe.Set(k, data, ttl)
+				bump(pipe, ttl, k)
+			}, []string{key}, nil)
+		}, k)
+
+		if err != redis.TxFailedErr {
+			return wrapError(op, key, err)
+		}
+	}
+}
+
+// IncrBy adds delta to the integer value of key and returns the new value.
+func (s *Driver) IncrBy(key string, delta int64) (n int64, err error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if s.closed {
+		return 0, wrapError("incr", key, driver.ErrClosed)
+	}
+
+	if !s.native() {
+		err = s.modify("incr", key, func(data []byte) (res []byte, err error) {
+			n, res, err = driver.Add(s.codec, data, delta)
+			return
+		})
+
+		return
+	}
+
+	var cmd *redis.IntCmd
+
+	err = s.pipelined(func(pipe *redis.Pipeline) error {
+		cmd = pipe.IncrBy(s.key(key), delta)
+		bump(pipe, keepTTL, s.key(key))
+		return s.reindex(pipe, []string{key}, nil)
+	})
+
+	if err != nil {
+		return 0, wrapError("incr", key, err)
+	}
+
+	return cmd.Val(), nil
+}
+
+// IncrByFloat adds delta to the float value of key and returns the new value.
+func (s *Driver) IncrByFloat(key string, delta float64) (n float64, err error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if s.closed {
+		return 0, wrapError("incr", key, driver.ErrClosed)
+	}
+
+	if !s.native() {
+		err = s.modify("incr", key, func(data []byte) (res []byte, err error) {
+			n, res, err = driver.Add(s.codec, data, delta)
+			return
+		})
+
+		return
+	}
+
+	var cmd *redis.FloatCmd
+
+	err = s.pipelined(func(pipe *redis.Pipeline) error {
+		cmd = pipe.IncrByFloat(s.key(key), delta)
+		bump(pipe, keepTTL, s.key(key))
+		return s.reindex(pipe, []string{key}, nil)
+	})
+
+	if err != nil {
+		return 0, wrapError("incr", key, err)
+	}
+
+	return cmd.Val(), nil
+}
@@ -0,0 +1,40 @@
+package redis
+
+import "github.com/frozzare/go-store/driver"
+
+// KeysMatch returns the keys with the prefix that
+// matches the pattern using SCAN MATCH.
+func (s *Driver) KeysMatch(pattern string) ([]string, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if s.closed {
+		return nil, wrapError("keys", "", driver.ErrClosed)
+	}
+
+	keys, err := s.find(pattern)
+
+	if err != nil {
+		return nil, wrapError("keys", "", err)
+	}
+
+	return keys, nil
+}
+
+// CountMatch returns the number of keys with the prefix that matches the pattern.
+func (s *Driver) CountMatch(pattern string) (int64, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if s.closed {
+		return 0, wrapError("count", "", driver.ErrClosed)
+	}
+
+	keys, err := s.find(pattern)
+
+	if err != nil {
+		return 0, wrapError("count", "", err)
+	}
+
+	return int64(len(keys)), nil
+}
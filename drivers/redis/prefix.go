@@ -0,0 +1,195 @@
+package redis
+
+import (
+	"strings"
+
+	"github.com/frozzare/go-store/driver"
+	"gopkg.in/redis.v5"
+)
+
+// batchSize is the COUNT hint SCAN is called with when all
+// keys is read, each page is unlinked with one command.
+const batchSize = 1000
+
+const (
+	// reserved is the namespace of the keys and channels the driver
+	// uses itself, keys that starts with it is not listed.
+	reserved = "__go-store:"
+
+	// indexKey is the sorted set that holds the keys written by
+	// the driver, followed by the prefix of the driver.
+	// The keys has the same score, so they is sorted in byte order.
+	indexKey = reserved + "index:"
+
+	// flushChannel is the channel Flush publish to, followed by the
+	// prefix of the driver, since Redis has no keyspace notification
+	// for FLUSHALL.
+	flushChannel = reserved + "flush:"
+
+	// versionPrefix is the prefix of the companion keys that
+	// holds the versions of the keys, see versionKey.
+	versionPrefix = reserved + "version:"
+)
+
+// key returns the key with the prefix.
+func (s *Driver) key(key string) string {
+	return s.prefix + key
+}
+
+// keys returns the keys with the prefix.
+func (s *Driver) keys(keys []string) []string {
+	res := make([]string, len(keys))
+
+	for i, key := range keys {
+		res[i] = s.key(key)
+	}
+
+	return res
+}
+
+// trim returns the key without the prefix.
+func (s *Driver) trim(key string) string {
+	return strings.TrimPrefix(key, s.prefix)
+}
+
+// index returns the key of the key index of the driver, which is
+// in the reserved namespace and not one of the keys with the prefix.
+func (s *Driver) index() string {
+	return indexKey + s.prefix
+}
+
+// flushChannel returns the channel Flush publish to.
+func (s *Driver) flushChannel() string {
+	return flushChannel + s.prefix
+}
+
+// internal reports if the key with the prefix is one
+// of the keys in the reserved namespace.
+func internal(key string) bool {
+	return strings.HasPrefix(key, reserved)
+}
+
+// listed returns the keys without the reserved keys and the prefix.
+func (s *Driver) listed(keys []string) []string {
+	res := keys[:0]
+
+	for _, key := range keys {
+		if !internal(key) {
+			res = append(res, s.trim(key))
+		}
+	}
+
+	return res
+}
+
+// Prefix returns a driver that shares the client but prefixes
+// all keys with prefix, after the prefix of the driver itself.
+// Closing it does not close the client.
+func (s *Driver) Prefix(prefix string) (driver.Driver, error) {
+	return &Driver{
+		client:    s.client,
+		codec:     s.codec,
+		prefix:    s.key(prefix),
+		flushMode: s.flushMode,
+		indexed:   s.indexed,
+		notify:    s.notify,
+		db:        s.db,
+	}, nil
+}
+
+// flushScan removes the keys with the prefix, their versions and the
+// key index of the driver with SCAN MATCH and UNLINK. The versions
+// has the prefix after the hash tag brace if the key has no hash tag.
+func (s *Driver) flushScan() error {
+	patterns := []string{
+		escape(s.prefix) + "*",
+		versionPrefix + escape(s.prefix) + "*",
+		versionPrefix + "{" + escape(s.prefix) + "*",
+	}
+
+	for _, pattern := range patterns {
+		if err := s.scanMatch(pattern, s.unlink); err != nil {
+			return err
+		}
+	}
+
+	if s.indexed {
+		return s.client.Del(s.index()).Err()
+	}
+
+	return nil
+}
+
+// find returns the keys without the prefix that matches the pattern,
+// the reserved keys is left out.
+func (s *Driver) find(pattern string) ([]string, error) {
+	keys := []string{}
+	seen := make(map[string]bool)
+
+	err := s.scan(pattern, func(_ Client, res []string) error {
+		for _, key := range res {
+			if !seen[key] && !internal(key) {
+				seen[key] = true
+				keys = append(keys, s.trim(key))
+			}
+		}
+
+		return nil
+	})
+
+	return keys, err
+}
+
+// unlink removes the keys from the server c with UNLINK, which frees
+// the memory in the background, or with DEL if the server is older
+// than Redis 4. A cluster node only accepts keys in the same slot in
+// one command, so the keys is removed one by one in a pipeline there.
+func (s *Driver) unlink(c Client, keys []string) error {
+	if _, ok := s.client.(*redis.ClusterClient); ok {
+		_, err := c.Pipelined(func(pipe *redis.Pipeline) error {
+			for _, key := range keys {
+				pipe.Process(unlinkCmd(key))
+			}
+
+			return nil
+		})
+
+		if isUnknown(err) {
+			_, err = c.Pipelined(func(pipe *redis.Pipeline) error {
+				for _, key := range keys {
+					pipe.Del(key)
+				}
+
+				return nil
+			})
+		}
+
+		return err
+	}
+
+	cmd := unlinkCmd(keys...)
+	c.Process(cmd)
+
+	if isUnknown(cmd.Err()) {
+		return c.Del(keys...).Err()
+	}
+
+	return cmd.Err()
+}
+
+// unlinkCmd returns a UNLINK command for the keys.
+func unlinkCmd(keys ...string) *redis.IntCmd {
+	args := make([]interface{}, len(keys)+1)
+	args[0] = "unlink"
+
+	for i, key := range keys {
+		args[i+1] = key
+	}
+
+	return redis.NewIntCmd(args...)
+}
+
+// isUnknown reports if the error is a unknown command error.
+func isUnknown(err error) bool {
+	return err != nil && strings.HasPrefix(err.Error(), "ERR unknown command")
+}
@@ -0,0 +1,105 @@
+package redis
+
+import (
+	"github.com/frozzare/go-store/driver"
+	"gopkg.in/redis.v5"
+)
+
+// members returns the keys as sorted set members.
+func members(keys ...string) []redis.Z {
+	z := make([]redis.Z, len(keys))
+
+	for i, key := range keys {
+		z[i] = redis.Z{Member: key}
+	}
+
+	return z
+}
+
+// Range returns up to limit keys and values where start <= key < end
+// using ZRANGEBYLEX on the key index. Only keys written by the driver
+// is in the index, deleted and expired keys is removed from it when
+// found. ErrNotSupported is returned if the index is not kept.
+func (s *Driver) Range(start, end string, limit int, reverse bool) ([]driver.KV, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if s.closed {
+		return nil, wrapError("range", "", driver.ErrClosed)
+	}
+
+	if !s.indexed {
+		return nil, wrapError("range", "", driver.ErrNotSupported)
+	}
+
+	opt := redis.ZRangeBy{Min: "[" + start, Max: "+"}
+	if end != "" {
+		opt.Max = "(" + end
+	}
+
+	var pairs []driver.KV
+
+	for {
+		if limit > 0 {
+			opt.Count = int64(limit - len(pairs))
+		}
+
+		var keys []string
+		var err error
+
+		if reverse {
+			keys, err = s.client.ZRevRangeByLex(s.index(), opt).Result()
+		} else {
+			keys, err = s.client.ZRangeByLex(s.index(), opt).Result()
+		}
+
+		if err != nil {
+			return nil, wrapError("range", "", err)
+		}
+
+		if len(keys) == 0 {
+			return pairs, nil
+		}
+
+		values, err := s.mget(s.keys(keys))
+
+		if err != nil {
+			return nil, wrapError("range", "", err)
+		}
+
+		var stale []interface{}
+
+		for i, v := range values {
+			str, ok := v.(string)
+
+			if !ok {
+				stale = append(stale, keys[i])
+				continue
+			}
+
+			value, err := driver.Decode(s.codec, []byte(str), nil)
+
+			if err != nil {
+				return nil, wrapError("range", keys[i], err)
+			}
+
+			pairs = append(pairs, driver.KV{Key: keys[i], Value: value})
+		}
+
+		if len(stale) == 0 {
+			return pairs, nil
+		}
+
+		if err := s.client.ZRem(s.index(), stale...).Err(); err != nil {
+			return nil, wrapError("range", "", err)
+		}
+
+		if limit < 1 {
+			return pairs, nil
+		}
+
+		// The stale keys is gone from the index, so the
+		// offset only moves past the keys that was found.
+		opt.Offset += int64(len(keys) - len(stale))
+	}
+}
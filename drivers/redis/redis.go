@@ -1,8 +1,8 @@
 package redis
 
 import (
-	"encoding/json"
-	"reflect"
+	"context"
+	"sync"
 
 	"github.com/frozzare/go-store/driver"
 	"gopkg.in/redis.v5"
@@ -10,24 +10,54 @@ import (
 
 // Driver represents a Redis driver.
 type Driver struct {
-	client *redis.Client
+	client    Client
+	codec     driver.Codec
+	prefix    string
+	flushMode FlushMode
+	indexed   bool
+	notify    bool
+
+	// db is the database index of the client, which the keyspace
+	// notifications Watch subscribes to is named after.
+	db int
+
+	// owned is set if the client was created by the driver, so
+	// Close closes it. A client set with WithClient or shared by
+	// Prefix is owned by someone else.
+	owned  bool
+	closed bool
+
+	// lock is read locked by the calls, so Close waits for the calls
+	// in flight before the client is closed.
+	lock sync.RWMutex
 }
 
-// Open creates a new Redis store.
+// init registers the driver with the name redis.
+func init() {
+	driver.RegisterFactory("redis", Open)
+}
+
+// Open creates a new Redis store. The args is a Config, Options
+// such as WithClient and WithPrefix, a driver.Codec or the positional
+// *redis.Options, *redis.ClusterOptions, *redis.FailoverOptions,
+// *redis.RingOptions or Client.
 func Open(args ...interface{}) (driver.Driver, error) {
-	var client *redis.Client
-
-	if len(args) > 0 && args[0] != nil {
-		client = redis.NewClient(args[0].(*redis.Options))
-	} else {
-		client = redis.NewClient(&redis.Options{
-			Addr:     "localhost:6379",
-			Password: "",
-			DB:       0,
-		})
+	config, err := newConfig(args)
+
+	if err != nil {
+		return nil, err
 	}
 
-	return &Driver{client: client}, nil
+	return &Driver{
+		client:    newClient(config),
+		codec:     config.Codec,
+		prefix:    config.Prefix,
+		flushMode: config.FlushMode,
+		indexed:   config.Index,
+		notify:    config.Notify,
+		db:        database(config),
+		owned:     config.Client == nil,
+	}, nil
 }
 
 // Open creates a new Redis store with a specified instance.
@@ -35,82 +65,293 @@ func (s *Driver) Open(args ...interface{}) (driver.Driver, error) {
 	return Open(args...)
 }
 
+// Capabilities returns the optional features the driver supports
+// with its client and config. Transactions needs a single server,
+// compare-and-set a client with WATCH, which a ring lacks, Watch needs
+// the keyspace notifications of a single server and Range needs the
+// key index. The client of redis.v5 ignores the context of WithContext,
+// so the Context methods can't abort a command and CapContext is not
+// reported even though the driver implements driver.ContextDriver.
+func (s *Driver) Capabilities() driver.Capabilities {
+	c := driver.CapTTL | driver.CapBatch | driver.CapScan |
+		driver.CapMatch | driver.CapCounter | driver.CapConditional | driver.CapPrefix
+
+	if s.indexed {
+		c |= driver.CapRange
+	}
+
+	if _, ok := s.client.(watcher); ok {
+		c |= driver.CapCAS
+
+		if !s.sharded() {
+			c |= driver.CapTx
+		}
+	}
+
+	if _, ok := s.client.(*redis.Client); ok {
+		c |= driver.CapWatch
+	}
+
+	return c
+}
+
+// wrapError maps Redis errors onto the driver errors
+// and records the operation and key.
+func wrapError(op, key string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	switch {
+	case err == redis.Nil:
+		err = driver.ErrNotFound
+	case err == redis.TxFailedErr:
+		err = driver.ErrConflict
+	case err.Error() == "redis: client is closed":
+		err = driver.ErrClosed
+	}
+
+	return &driver.Error{Op: op, Key: key, Driver: "redis", Err: err}
+}
+
 // Count returns numbers of keys in store.
 func (s *Driver) Count() (int64, error) {
-	return s.client.DbSize().Result()
+	return s.CountContext(context.Background())
+}
+
+// CountContext returns numbers of keys with the prefix in store.
+// The keys is counted with SCAN, so it's not a atomic snapshot. The
+// scan is not stopped when the context is done, see driver.Run.
+func (s *Driver) CountContext(ctx context.Context) (int64, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if s.closed {
+		return 0, wrapError("count", "", driver.ErrClosed)
+	}
+
+	var keys []string
+
+	err := driver.Run(ctx, func() (err error) {
+		keys, err = s.find("*")
+		return
+	})
+
+	if err != nil {
+		return 0, wrapError("count", "", err)
+	}
+
+	return int64(len(keys)), nil
 }
 
 // Exists returns true when a key exists false when not existing in store.
 func (s *Driver) Exists(key string) (bool, error) {
-	return s.client.Exists(key).Result()
+	return s.ExistsContext(context.Background(), key)
+}
+
+// ExistsContext returns true when a key exists false when not existing
+// in store. The command is not aborted when the context is done.
+func (s *Driver) ExistsContext(ctx context.Context, key string) (bool, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if s.closed {
+		return false, wrapError("exists", key, driver.ErrClosed)
+	}
+
+	var exists bool
+
+	err := driver.Run(ctx, func() (err error) {
+		exists, err = s.client.Exists(s.key(key)).Result()
+		return
+	})
+
+	if err != nil {
+		return false, wrapError("exists", key, err)
+	}
+
+	return exists, nil
 }
 
 // Get returns the value for a key if any.
 func (s *Driver) Get(key string, args ...interface{}) (interface{}, error) {
-	res, err := s.client.Get(key).Result()
+	return s.GetContext(context.Background(), key, args...)
+}
 
-	if len(res) == 0 {
-		return nil, err
+// GetContext returns the value for a key if any. The command
+// is not aborted when the context is done.
+func (s *Driver) GetContext(ctx context.Context, key string, args ...interface{}) (interface{}, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if s.closed {
+		return nil, wrapError("get", key, driver.ErrClosed)
 	}
 
-	var value interface{}
+	if err := driver.CheckArgs(args); err != nil {
+		return nil, wrapError("get", key, err)
+	}
+
+	var res []byte
+
+	err := driver.Run(ctx, func() (err error) {
+		res, err = s.client.Get(s.key(key)).Bytes()
+		return
+	})
 
-	if len(args) > 0 {
-		value = args[0]
+	if err != nil {
+		return nil, wrapError("get", key, err)
 	}
 
-	if err = json.Unmarshal([]byte(res), &value); err == nil {
-		if len(args) > 0 {
-			return nil, nil
-		}
+	value, err := driver.Decode(s.codec, res, args)
 
-		return value, nil
+	if err != nil {
+		return nil, wrapError("get", key, err)
 	}
 
-	return res, nil
+	return value, nil
 }
 
 // Keys returns a string slice with all keys.
 func (s *Driver) Keys() ([]string, error) {
-	res, err := s.client.Keys("*").Result()
+	return s.KeysContext(context.Background())
+}
+
+// KeysContext returns a string slice with all keys with the prefix
+// using SCAN on every master, the keys is returned without the prefix.
+// The scan is not stopped when the context is done.
+func (s *Driver) KeysContext(ctx context.Context) ([]string, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
 
-	if len(res) == 0 {
-		return []string{}, err
+	if s.closed {
+		return []string{}, wrapError("keys", "", driver.ErrClosed)
 	}
 
-	return res, nil
+	var keys []string
+
+	err := driver.Run(ctx, func() (err error) {
+		keys, err = s.find("*")
+		return
+	})
+
+	if err != nil {
+		return []string{}, wrapError("keys", "", err)
+	}
+
+	return keys, nil
 }
 
 // Set key with value in store.
 func (s *Driver) Set(key string, value interface{}) error {
-	if reflect.TypeOf(value).Kind() != reflect.String {
-		value, err := json.Marshal(value)
+	return s.SetContext(context.Background(), key, value)
+}
 
-		if err != nil {
-			return err
-		}
+// SetContext key with value in store. The key can still be set after
+// the context is done, since the command is not aborted.
+func (s *Driver) SetContext(ctx context.Context, key string, value interface{}) error {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if s.closed {
+		return wrapError("set", key, driver.ErrClosed)
+	}
+
+	data, err := s.codec.Marshal(value)
 
-		return s.client.Set(key, value, 0).Err()
+	if err != nil {
+		return wrapError("set", key, err)
 	}
 
-	return s.client.Set(key, []byte(value.(string)), 0).Err()
+	return wrapError("set", key, driver.Run(ctx, func() error {
+		err := s.pipelined(func(pipe *redis.Pipeline) error {
+			pipe.Set(s.key(key), data, 0)
+			bump(pipe, 0, s.key(key))
+			return s.reindex(pipe, []string{key}, nil)
+		})
+
+		return err
+	}))
 }
 
 // Delete key from store.
 func (s *Driver) Delete(key string) error {
-	return s.client.Del(key).Err()
+	return s.DeleteContext(context.Background(), key)
 }
 
-// Close does not exists for Redis driver.
+// DeleteContext key from store. The key can still be deleted after
+// the context is done, since the command is not aborted.
+func (s *Driver) DeleteContext(ctx context.Context, key string) error {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if s.closed {
+		return wrapError("delete", key, driver.ErrClosed)
+	}
+
+	return wrapError("delete", key, driver.Run(ctx, func() error {
+		err := s.pipelined(func(pipe *redis.Pipeline) error {
+			pipe.Del(s.key(key))
+			unversion(pipe, s.key(key))
+			return s.reindex(pipe, nil, []string{key})
+		})
+
+		return err
+	}))
+}
+
+// Close waits for the calls in flight and closes the client if it was
+// created by the driver, a client set with WithClient or shared by
+// Prefix is left open. The calls made after Close returns ErrClosed.
 func (s *Driver) Close() error {
-	return nil
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.closed {
+		return nil
+	}
+
+	s.closed = true
+
+	if !s.owned {
+		return nil
+	}
+
+	return wrapError("close", "", s.client.Close())
 }
 
-// Flush will remove all keys and values from the store.
+// Flush will remove all keys with the prefix from the store.
 func (s *Driver) Flush() error {
-	if _, err := s.client.FlushAll().Result(); err != nil {
-		return err
+	return s.FlushContext(context.Background())
+}
+
+// FlushContext will remove all keys with the prefix and the key index
+// from the store, or all keys in the database if the flush mode is
+// FlushDB. Every master in a cluster or ring is flushed. The flush
+// keeps going in the background when the context is done.
+func (s *Driver) FlushContext(ctx context.Context) error {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if s.closed {
+		return wrapError("flush", "", driver.ErrClosed)
 	}
 
-	return nil
+	return wrapError("flush", "", driver.Run(ctx, func() error {
+		var err error
+
+		if s.flushMode == FlushDB {
+			err = s.forEachMaster(func(c Client) error {
+				return c.FlushDb().Err()
+			})
+		} else {
+			err = s.flushScan()
+		}
+
+		if err != nil {
+			return err
+		}
+
+		return s.client.Publish(s.flushChannel(), "flush").Err()
+	}))
 }
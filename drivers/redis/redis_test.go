@@ -1,9 +1,19 @@
 package redis
 
 import (
+	"context"
+	"errors"
+	"net/url"
+	"sort"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/frozzare/go-assert"
+	"github.com/frozzare/go-store/codec"
+	"github.com/frozzare/go-store/driver"
+	"github.com/frozzare/go-store/storetest"
+	"gopkg.in/redis.v5"
 )
 
 /*
@@ -143,3 +153,675 @@ func TestGetSetSimpleStruct(t *testing.T) {
 
 	s.Delete("name")
 }
+
+func TestGetSetContext(t *testing.T) {
+	d, _ := Open()
+	s := d.(driver.ContextDriver)
+	ctx := context.Background()
+
+	v, _ := s.GetContext(ctx, "name")
+	assert.Nil(t, v)
+
+	s.SetContext(ctx, "name", "Fredrik")
+
+	v, _ = s.GetContext(ctx, "name")
+	assert.Equal(t, "Fredrik", v.(string))
+
+	s.DeleteContext(ctx, "name")
+}
+
+func TestCanceledContext(t *testing.T) {
+	d, _ := Open()
+	s := d.(driver.ContextDriver)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	assert.True(t, errors.Is(s.SetContext(ctx, "name", "Fredrik"), context.Canceled))
+
+	e, _ := s.Exists("name")
+	assert.False(t, e)
+
+	_, err := s.KeysContext(ctx)
+	assert.True(t, errors.Is(err, context.Canceled))
+}
+
+func TestNotFound(t *testing.T) {
+	s, _ := Open()
+
+	v, err := s.Get("missing")
+	assert.Nil(t, v)
+	assert.True(t, errors.Is(err, driver.ErrNotFound))
+
+	e, err := s.Exists("missing")
+	assert.False(t, e)
+	assert.Nil(t, err)
+
+	assert.Nil(t, s.Delete("missing"))
+}
+
+func TestGetSetNumericString(t *testing.T) {
+	s, _ := Open()
+
+	s.Set("number", "123")
+
+	v, _ := s.Get("number")
+	assert.Equal(t, "123", v.(string))
+
+	s.Delete("number")
+}
+
+func TestCustomCodec(t *testing.T) {
+	s, _ := Open(codec.Gob{})
+
+	s.Set("name", &Person{Name: "Fredrik"})
+
+	var p *Person
+	_, err := s.Get("name", &p)
+	assert.Nil(t, err)
+	assert.Equal(t, "Fredrik", p.Name)
+
+	s.Delete("name")
+}
+
+func TestEnvelopeTypes(t *testing.T) {
+	s, _ := Open(codec.Envelope{})
+	now := time.Now().UTC()
+
+	s.Set("int", int64(1<<62+1))
+	s.Set("nil", nil)
+	s.Set("bytes", []byte("Fredrik"))
+	s.Set("time", now)
+
+	v, _ := s.Get("int")
+	assert.Equal(t, int64(1<<62+1), v.(int64))
+
+	v, err := s.Get("nil")
+	assert.Nil(t, v)
+	assert.Nil(t, err)
+
+	v, _ = s.Get("bytes")
+	assert.Equal(t, "Fredrik", string(v.([]byte)))
+
+	v, _ = s.Get("time")
+	assert.True(t, now.Equal(v.(time.Time)))
+
+	s.Flush()
+}
+
+func TestTTL(t *testing.T) {
+	d, _ := Open()
+	s := d.(driver.TTLDriver)
+	d.Flush()
+
+	assert.True(t, errors.Is(s.SetWithTTL("name", "Fredrik", 0), driver.ErrInvalidArgs))
+
+	s.SetWithTTL("name", "Fredrik", time.Minute)
+
+	ttl, err := s.TTL("name")
+	assert.Nil(t, err)
+	assert.True(t, ttl > 0 && ttl <= time.Minute)
+
+	d.Set("name", "Fredrik")
+
+	ttl, _ = s.TTL("name")
+	assert.Equal(t, driver.NoExpiration, ttl)
+
+	s.Expire("name", time.Minute)
+	assert.Nil(t, s.Persist("name"))
+
+	ttl, _ = s.TTL("name")
+	assert.Equal(t, driver.NoExpiration, ttl)
+
+	assert.Nil(t, s.Expire("name", 50*time.Millisecond))
+	time.Sleep(100 * time.Millisecond)
+
+	v, err := d.Get("name")
+	assert.Nil(t, v)
+	assert.True(t, errors.Is(err, driver.ErrNotFound))
+
+	e, _ := d.Exists("name")
+	assert.False(t, e)
+
+	keys, _ := d.Keys()
+	assert.Equal(t, 0, len(keys))
+
+	count, _ := d.Count()
+	assert.Equal(t, int64(0), count)
+
+	_, err = s.TTL("name")
+	assert.True(t, errors.Is(err, driver.ErrNotFound))
+	assert.True(t, errors.Is(s.Expire("name", time.Minute), driver.ErrNotFound))
+	assert.True(t, errors.Is(s.Persist("name"), driver.ErrNotFound))
+
+	d.Flush()
+}
+
+func TestMulti(t *testing.T) {
+	d, _ := Open()
+	s := d.(driver.BatchDriver)
+
+	assert.Nil(t, s.SetMulti(map[string]interface{}{"a": "1", "b": "2"}))
+
+	values, err := s.GetMulti([]string{"a", "b", "c"})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(values))
+	assert.Equal(t, "1", values["a"].(string))
+
+	var strs map[string]string
+	_, err = s.GetMulti([]string{"a", "b", "c"}, &strs)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(strs))
+	assert.Equal(t, "2", strs["b"])
+
+	assert.Nil(t, s.DeleteMulti("a", "b", "c"))
+
+	values, _ = s.GetMulti([]string{"a", "b"})
+	assert.Equal(t, 0, len(values))
+}
+
+func TestUpdate(t *testing.T) {
+	d, _ := Open()
+	s := d.(driver.TxDriver)
+
+	d.Set("a", "1")
+	d.Set("b", "2")
+
+	err := s.Update(func(tx driver.Tx) error {
+		v, err := tx.Get("a")
+		if err != nil {
+			return err
+		}
+
+		tx.Set("b", v)
+		tx.Delete("a")
+
+		e, _ := tx.Exists("a")
+		assert.False(t, e)
+
+		return nil
+	})
+	assert.Nil(t, err)
+
+	v, _ := d.Get("b")
+	assert.Equal(t, "1", v.(string))
+
+	e, _ := d.Exists("a")
+	assert.False(t, e)
+
+	rollback := errors.New("rollback")
+
+	err = s.Update(func(tx driver.Tx) error {
+		tx.Set("b", "3")
+		tx.Set("c", "3")
+
+		return rollback
+	})
+	assert.Equal(t, rollback, err)
+
+	v, _ = d.Get("b")
+	assert.Equal(t, "1", v.(string))
+
+	e, _ = d.Exists("c")
+	assert.False(t, e)
+
+	d.Flush()
+}
+
+func TestScan(t *testing.T) {
+	d, _ := Open()
+	s := d.(driver.ScanDriver)
+	d.Flush()
+
+	for _, key := range []string{"a:1", "a:2", "a:3", "b:1"} {
+		d.Set(key, "Fredrik")
+	}
+
+	var keys []string
+	var cursor string
+
+	for {
+		page, next, err := s.Scan("a:", cursor, 2)
+		assert.Nil(t, err)
+
+		keys = append(keys, page...)
+
+		if next == "" {
+			break
+		}
+
+		cursor = next
+	}
+
+	sort.Strings(keys)
+	assert.Equal(t, "a:1,a:2,a:3", strings.Join(keys, ","))
+
+	keys, cursor, _ = s.Scan("", "", 0)
+	assert.Equal(t, 4, len(keys))
+	assert.Equal(t, "", cursor)
+
+	d.Flush()
+}
+
+func TestKeysCountMatch(t *testing.T) {
+	d, _ := Open()
+	s := d.(driver.MatchDriver)
+	d.Flush()
+
+	for _, key := range []string{"session:1", "session:2", "user:1:profile", "user:12:profile"} {
+		d.Set(key, "Fredrik")
+	}
+
+	keys, err := s.KeysMatch("session:*")
+	assert.Nil(t, err)
+
+	sort.Strings(keys)
+	assert.Equal(t, "session:1,session:2", strings.Join(keys, ","))
+
+	count, err := s.CountMatch("user:?:profile")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), count)
+
+	count, _ = s.CountMatch("user:[0-9]*:profile")
+	assert.Equal(t, int64(2), count)
+
+	count, _ = s.CountMatch("*")
+	assert.Equal(t, int64(4), count)
+
+	d.Flush()
+}
+
+func TestRange(t *testing.T) {
+	d, _ := Open(WithIndex())
+	s := d.(driver.RangeDriver)
+	d.Flush()
+
+	for _, key := range []string{"a", "b", "c", "d"} {
+		d.Set(key, key)
+	}
+
+	n, _ := d.(*Driver).client.ZCard(d.(*Driver).index()).Result()
+	assert.Equal(t, int64(4), n)
+
+	list := func(pairs []driver.KV) string {
+		var keys []string
+
+		for _, p := range pairs {
+			keys = append(keys, p.Key)
+		}
+
+		return strings.Join(keys, ",")
+	}
+
+	pairs, err := s.Range("b", "d", 0, false)
+	assert.Nil(t, err)
+	assert.Equal(t, "b,c", list(pairs))
+	assert.Equal(t, "b", pairs[0].Value.(string))
+
+	pairs, _ = s.Range("b", "d", 0, true)
+	assert.Equal(t, "c,b", list(pairs))
+
+	pairs, _ = s.Range("", "", 3, false)
+	assert.Equal(t, "a,b,c", list(pairs))
+
+	pairs, _ = s.Range("", "", 3, true)
+	assert.Equal(t, "d,c,b", list(pairs))
+
+	pairs, _ = s.Range("b", "", 0, true)
+	assert.Equal(t, "d,c,b", list(pairs))
+
+	d.Flush()
+}
+
+func TestRangeTTL(t *testing.T) {
+	d, _ := Open(WithIndex())
+	s := d.(driver.RangeDriver)
+	ttl := d.(driver.TTLDriver)
+	d.Flush()
+
+	d.Set("a", "a")
+	ttl.SetWithTTL("b", "b", time.Minute)
+	ttl.SetWithTTL("c", "c", 50*time.Millisecond)
+	d.Set("d", "d")
+	ttl.Expire("d", 50*time.Millisecond)
+	ttl.SetWithTTL("e", "e", 50*time.Millisecond)
+	ttl.Persist("e")
+
+	list := func(pairs []driver.KV) string {
+		var keys []string
+
+		for _, p := range pairs {
+			keys = append(keys, p.Key)
+		}
+
+		return strings.Join(keys, ",")
+	}
+
+	// Keys with a ttl is in the index until they expires.
+	pairs, err := s.Range("", "", 0, false)
+	assert.Nil(t, err)
+	assert.Equal(t, "a,b,c,d,e", list(pairs))
+
+	time.Sleep(100 * time.Millisecond)
+
+	pairs, _ = s.Range("", "", 2, false)
+	assert.Equal(t, "a,b", list(pairs))
+
+	pairs, _ = s.Range("b", "", 0, false)
+	assert.Equal(t, "b,e", list(pairs))
+
+	// The expired keys is removed from the index by Range.
+	n, _ := d.(*Driver).client.ZCard(d.(*Driver).index()).Result()
+	assert.Equal(t, int64(3), n)
+
+	d.Flush()
+}
+
+func TestCompareAndSet(t *testing.T) {
+	d, _ := Open()
+	s := d.(driver.CASDriver)
+	d.Flush()
+
+	_, _, err := s.GetWithVersion("name")
+	assert.True(t, errors.Is(err, driver.ErrNotFound))
+
+	assert.Nil(t, s.CompareAndSet("name", "", "Fredrik"))
+	assert.True(t, errors.Is(s.CompareAndSet("name", "", "Fredrik"), driver.ErrConflict))
+
+	v, version, err := s.GetWithVersion("name")
+	assert.Nil(t, err)
+	assert.Equal(t, "Fredrik", v.(string))
+
+	d.Set("name", "Elli")
+
+	assert.True(t, errors.Is(s.CompareAndSet("name", version, "Fredrik"), driver.ErrConflict))
+
+	_, version, _ = s.GetWithVersion("name")
+	assert.Nil(t, s.CompareAndSet("name", version, "Fredrik"))
+
+	v, _ = d.Get("name")
+	assert.Equal(t, "Fredrik", v.(string))
+
+	d.Flush()
+}
+
+func TestCompareAndSetABA(t *testing.T) {
+	d, _ := Open()
+	s := d.(driver.CASDriver)
+	d.Flush()
+
+	d.Set("name", "Fredrik")
+	_, version, _ := s.GetWithVersion("name")
+
+	// The value is the same but the key has been written since.
+	d.Set("name", "Elli")
+	d.Set("name", "Fredrik")
+	assert.True(t, errors.Is(s.CompareAndSet("name", version, "Elli"), driver.ErrConflict))
+
+	_, version, _ = s.GetWithVersion("name")
+	d.Delete("name")
+	d.Set("name", "Fredrik")
+	assert.True(t, errors.Is(s.CompareAndSet("name", version, "Elli"), driver.ErrConflict))
+
+	_, version, _ = s.GetWithVersion("name")
+	d.(driver.ConditionalDriver).SetIfPresent("name", "Fredrik")
+	assert.True(t, errors.Is(s.CompareAndSet("name", version, "Elli"), driver.ErrConflict))
+
+	_, version, _ = s.GetWithVersion("name")
+	d.(driver.CounterDriver).IncrBy("counter", 1)
+	assert.Nil(t, s.CompareAndSet("name", version, "Elli"))
+
+	// The versions is not listed as keys and is removed by Flush.
+	keys, _ := d.Keys()
+	assert.Equal(t, 2, len(keys))
+
+	d.Flush()
+
+	n, _ := d.(*Driver).client.Exists(versionKey("name")).Result()
+	assert.False(t, n)
+}
+
+func TestIncrBy(t *testing.T) {
+	d, _ := Open()
+	s := d.(driver.CounterDriver)
+	d.Flush()
+
+	n, err := s.IncrBy("counter", 5)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(5), n)
+
+	n, err = s.IncrBy("counter", -2)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), n)
+
+	v, err := d.Get("counter")
+	assert.Nil(t, err)
+	assert.Equal(t, float64(3), v.(float64))
+
+	f, err := s.IncrByFloat("counter", 0.5)
+	assert.Nil(t, err)
+	assert.Equal(t, 3.5, f)
+
+	d.Set("name", "Fredrik")
+	_, err = s.IncrBy("name", 1)
+	assert.NotNil(t, err)
+
+	d.Flush()
+}
+
+func TestSetIf(t *testing.T) {
+	d, _ := Open()
+	s := d.(driver.ConditionalDriver)
+	d.Flush()
+
+	ok, err := s.SetIfPresent("name", "Fredrik")
+	assert.Nil(t, err)
+	assert.False(t, ok)
+
+	ok, err = s.SetIfAbsent("name", "Fredrik")
+	assert.Nil(t, err)
+	assert.True(t, ok)
+
+	ok, err = s.SetIfAbsent("name", "Elli")
+	assert.Nil(t, err)
+	assert.False(t, ok)
+
+	v, _ := d.Get("name")
+	assert.Equal(t, "Fredrik", v.(string))
+
+	ok, err = s.SetIfPresent("name", "Elli")
+	assert.Nil(t, err)
+	assert.True(t, ok)
+
+	v, _ = d.Get("name")
+	assert.Equal(t, "Elli", v.(string))
+
+	d.(driver.TTLDriver).SetWithTTL("session", "x", 50*time.Millisecond)
+	time.Sleep(100 * time.Millisecond)
+
+	ok, err = s.SetIfAbsent("session", "y")
+	assert.Nil(t, err)
+	assert.True(t, ok)
+
+	d.Flush()
+}
+
+func TestWatch(t *testing.T) {
+	d, _ := Open()
+	d.Flush()
+
+	// The notifications is only enabled if the driver is allowed to.
+	d.(*Driver).client.(*redis.Client).ConfigSet("notify-keyspace-events", "")
+	_, err := d.(driver.WatchDriver).Watch(context.Background(), "user:")
+	assert.True(t, errors.Is(err, driver.ErrNotSupported))
+
+	d, _ = Open(WithNotify())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events, err := d.(driver.WatchDriver).Watch(ctx, "user:")
+	assert.Nil(t, err)
+
+	d.Set("post:1", "Hello")
+	d.Set("user:1", "Fredrik")
+	d.Delete("user:1")
+	d.Flush()
+
+	for _, want := range []driver.Event{
+		{Type: driver.EventSet, Key: "user:1"},
+		{Type: driver.EventDelete, Key: "user:1"},
+		{Type: driver.EventFlush},
+	} {
+		select {
+		case e := <-events:
+			assert.Equal(t, want, e)
+		case <-time.After(time.Second):
+			t.Fatalf("no %s event", want.Type)
+		}
+	}
+
+	cancel()
+
+	for range events {
+	}
+}
+
+func TestParseURL(t *testing.T) {
+	u, _ := url.Parse("redis://:secret@example.com/2?read_timeout=1s&pool_size=5")
+	c, err := parseURL(u)
+	assert.Nil(t, err)
+	assert.Equal(t, "example.com:6379", c.Options.Addr)
+	assert.Equal(t, "secret", c.Options.Password)
+	assert.Equal(t, 2, c.Options.DB)
+	assert.Equal(t, time.Second, c.Options.ReadTimeout)
+	assert.Equal(t, 5, c.Options.PoolSize)
+	assert.Equal(t, FlushScan, c.FlushMode)
+
+	u, _ = url.Parse("redis://localhost/?flush=db&index=true&notify=true")
+	c, err = parseURL(u)
+	assert.Nil(t, err)
+	assert.Equal(t, FlushDB, c.FlushMode)
+	assert.True(t, c.Index)
+	assert.True(t, c.Notify)
+
+	u, _ = url.Parse("redis://localhost/?flush=all")
+	_, err = parseURL(u)
+	assert.True(t, errors.Is(err, driver.ErrInvalidArgs))
+
+	u, _ = url.Parse("redis://localhost/db")
+	_, err = parseURL(u)
+	assert.True(t, errors.Is(err, driver.ErrInvalidArgs))
+}
+
+func TestConfig(t *testing.T) {
+	client := redis.NewClient(&redis.Options{Addr: "localhost:6380"})
+
+	c, err := newConfig([]interface{}{WithClient(client), codec.Gob{}})
+	assert.Nil(t, err)
+	assert.Equal(t, client, c.Client)
+	assert.Equal(t, codec.Gob{}, c.Codec)
+
+	c, err = newConfig([]interface{}{redis.Options{Addr: "localhost:6380"}})
+	assert.Nil(t, err)
+	assert.Equal(t, "localhost:6380", c.Options.Addr)
+	assert.Equal(t, "", c.Prefix)
+	assert.Equal(t, FlushScan, c.FlushMode)
+
+	c, err = newConfig([]interface{}{WithPrefix("app:"), WithFlushMode(FlushDB), WithIndex(), WithNotify()})
+	assert.Nil(t, err)
+	assert.Equal(t, "app:", c.Prefix)
+	assert.Equal(t, FlushDB, c.FlushMode)
+	assert.True(t, c.Index)
+	assert.True(t, c.Notify)
+
+	_, err = Open("localhost:6379")
+	assert.True(t, errors.Is(err, driver.ErrInvalidArgs))
+}
+
+func TestCapabilities(t *testing.T) {
+	d := &Driver{client: redis.NewClient(&redis.Options{})}
+	assert.Equal(t, "ttl|batch|tx|scan|match|cas|counter|conditional|watch|prefix", driver.CapabilitiesOf(d).String())
+
+	_, err := d.Range("", "", 0, false)
+	assert.True(t, errors.Is(err, driver.ErrNotSupported))
+
+	d.indexed = true
+	assert.True(t, driver.CapabilitiesOf(d).Has(driver.CapRange))
+}
+
+func TestClose(t *testing.T) {
+	client := redis.NewClient(&redis.Options{Addr: "localhost:6380"})
+	defer client.Close()
+
+	d, _ := Open(WithClient(client))
+	p, _ := d.(driver.PrefixDriver).Prefix("a:")
+
+	assert.False(t, d.(*Driver).owned)
+	assert.False(t, p.(*Driver).owned)
+
+	assert.Nil(t, p.Close())
+	assert.Nil(t, d.Close())
+	assert.Nil(t, d.Close())
+
+	_, err := d.Get("name")
+	assert.True(t, errors.Is(err, driver.ErrClosed))
+	assert.True(t, errors.Is(p.Set("name", "Fredrik"), driver.ErrClosed))
+
+	d, _ = Open(WithOptions(&redis.Options{Addr: "localhost:6380"}))
+	assert.True(t, d.(*Driver).owned)
+	assert.Nil(t, d.Close())
+}
+
+func TestPrefix(t *testing.T) {
+	a, _ := Open(WithPrefix("a:"))
+	b, _ := Open(WithPrefix("b:"), WithIndex())
+	defer a.Flush()
+	defer b.Flush()
+
+	a.Set("name", "Fredrik")
+	b.Set("name", "Elli")
+	b.Set("age", 30)
+
+	v, _ := a.Get("name")
+	assert.Equal(t, "Fredrik", v)
+
+	c, _ := a.Count()
+	assert.Equal(t, int64(1), c)
+
+	k, _ := a.Keys()
+	assert.Equal(t, []string{"name"}, k)
+
+	kv, _ := b.(driver.RangeDriver).Range("", "", 0, false)
+	assert.Equal(t, 2, len(kv))
+	assert.Equal(t, "age", kv[0].Key)
+
+	assert.Nil(t, a.Flush())
+
+	c, _ = a.Count()
+	assert.Equal(t, int64(0), c)
+
+	c, _ = b.Count()
+	assert.Equal(t, int64(2), c)
+
+	// The prefix of a prefixed driver is added after its own.
+	p, _ := a.(driver.PrefixDriver).Prefix("users:")
+	p.Set("1", "Fredrik")
+
+	k, _ = a.Keys()
+	assert.Equal(t, []string{"users:1"}, k)
+
+	k, _ = p.Keys()
+	assert.Equal(t, []string{"1"}, k)
+}
+
+func TestPrefixConformance(t *testing.T) {
+	storetest.Run(t, func() driver.Driver {
+		d, _ := Open(WithPrefix("conformance:"))
+		return d
+	})
+}
+
+func TestConformance(t *testing.T) {
+	storetest.Run(t, func() driver.Driver {
+		d, _ := Open()
+		return d
+	})
+}
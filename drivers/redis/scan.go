@@ -0,0 +1,135 @@
+package redis
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/frozzare/go-store/driver"
+)
+
+// escape escapes the glob characters in a key so it can be used
+// as a literal in a SCAN MATCH pattern.
+func escape(key string) string {
+	var b strings.Builder
+
+	for _, c := range key {
+		switch c {
+		case '*', '?', '[', ']', '\\':
+			b.WriteByte('\\')
+		}
+
+		b.WriteRune(c)
+	}
+
+	return b.String()
+}
+
+// parseCursor returns the master and the SCAN cursor of the master
+// for a cursor. The cursor of a single server is the SCAN cursor and
+// the cursor of a cluster or ring is the index of the master and its
+// SCAN cursor separated by a colon.
+func parseCursor(cursor string, masters int) (int, uint64, error) {
+	if cursor == "" {
+		return 0, 0, nil
+	}
+
+	i := 0
+	c := cursor
+
+	if masters > 1 {
+		n := strings.IndexByte(cursor, ':')
+
+		if n < 0 {
+			return 0, 0, driver.ErrInvalidArgs
+		}
+
+		var err error
+
+		if i, err = strconv.Atoi(cursor[:n]); err != nil || i < 0 || i >= masters {
+			return 0, 0, driver.ErrInvalidArgs
+		}
+
+		c = cursor[n+1:]
+	}
+
+	next, err := strconv.ParseUint(c, 10, 64)
+
+	if err != nil {
+		return 0, 0, driver.ErrInvalidArgs
+	}
+
+	return i, next, nil
+}
+
+// formatCursor returns the cursor for the master
+// and the SCAN cursor of the master.
+func formatCursor(i int, c uint64, masters int) string {
+	if masters > 1 {
+		return strconv.Itoa(i) + ":" + strconv.FormatUint(c, 10)
+	}
+
+	return strconv.FormatUint(c, 10)
+}
+
+// Scan returns keys with the prefix starting at the cursor using
+// the SCAN command. The keys is not sorted and the limit is passed
+// as the COUNT hint, so a page can have more or less keys than the
+// limit and the same key can be returned more than once. A cluster
+// or ring is scanned one master at a time in the order of their
+// address, so the keys can be missed or returned again if the
+// masters is changed between the pages.
+func (s *Driver) Scan(prefix, cursor string, limit int) ([]string, string, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if s.closed {
+		return nil, "", wrapError("scan", "", driver.ErrClosed)
+	}
+
+	masters, err := s.masters()
+
+	if err != nil {
+		return nil, "", wrapError("scan", "", err)
+	}
+
+	if len(masters) == 0 {
+		return nil, "", nil
+	}
+
+	i, c, err := parseCursor(cursor, len(masters))
+
+	if err != nil {
+		return nil, "", wrapError("scan", "", err)
+	}
+
+	count := int64(limit)
+	if count < 1 {
+		count = batchSize
+	}
+
+	var keys []string
+
+	for {
+		res, next, err := masters[i].Scan(c, escape(s.key(prefix))+"*", count).Result()
+
+		if err != nil {
+			return nil, "", wrapError("scan", "", err)
+		}
+
+		keys = append(keys, s.listed(res)...)
+
+		if c = next; c == 0 {
+			i++
+		}
+
+		if i == len(masters) {
+			return keys, "", nil
+		}
+
+		// SCAN can return empty pages, so keep going until
+		// there is keys to return unless all keys is wanted.
+		if limit > 0 && len(keys) > 0 {
+			return keys, formatCursor(i, c, len(masters)), nil
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package redis
+
+import (
+	"github.com/frozzare/go-store/driver"
+	"gopkg.in/redis.v5"
+)
+
+// setIfScript sets a key with SET NX or XX and bumps its version
+// if it was set, so the version is written together with the value.
+var setIfScript = redis.NewScript(`
+if redis.call("SET", KEYS[1], ARGV[1], ARGV[2]) then
+	redis.call("SET", KEYS[2], ARGV[3], "NX")
+	redis.call("INCR", KEYS[2])
+	redis.call("PERSIST", KEYS[2])
+	return 1
+end
+return 0
+`)
+
+// setIf sets key to data with SET NX or XX and bumps the version
+// of the key in the same script, it reports if the key was set.
+func (s *Driver) setIf(key string, data []byte, mode string) (bool, error) {
+	k := s.key(key)
+	res, err := setIfScript.Run(s.client, []string{k, versionKey(k)}, data, mode, seed()).Result()
+
+	if err != nil || res != int64(1) {
+		return false, err
+	}
+
+	return true, s.reindex(s.client, []string{key}, nil)
+}
+
+// SetIfAbsent sets key to value if the key does not exist.
+func (s *Driver) SetIfAbsent(key string, value interface{}) (bool, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if s.closed {
+		return false, wrapError("set", key, driver.ErrClosed)
+	}
+
+	data, err := s.codec.Marshal(value)
+
+	if err != nil {
+		return false, wrapError("set", key, err)
+	}
+
+	ok, err := s.setIf(key, data, "NX")
+
+	if err != nil {
+		return false, wrapError("set", key, err)
+	}
+
+	return ok, nil
+}
+
+// SetIfPresent sets key to value if the key exist,
+// the ttl of the key is removed.
+func (s *Driver) SetIfPresent(key string, value interface{}) (bool, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if s.closed {
+		return false, wrapError("set", key, driver.ErrClosed)
+	}
+
+	data, err := s.codec.Marshal(value)
+
+	if err != nil {
+		return false, wrapError("set", key, err)
+	}
+
+	ok, err := s.setIf(key, data, "XX")
+
+	if err != nil {
+		return false, wrapError("set", key, err)
+	}
+
+	return ok, nil
+}
@@ -0,0 +1,138 @@
+package redis
+
+import (
+	"time"
+
+	"github.com/frozzare/go-store/driver"
+	"gopkg.in/redis.v5"
+)
+
+// SetWithTTL key with value in store that expires after the ttl.
+// The key is added to the key index, Range removes it from the
+// index when it finds that the key has expired.
+func (s *Driver) SetWithTTL(key string, value interface{}, ttl time.Duration) error {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if s.closed {
+		return wrapError("set", key, driver.ErrClosed)
+	}
+
+	if ttl <= 0 {
+		return wrapError("set", key, driver.ErrInvalidArgs)
+	}
+
+	data, err := s.codec.Marshal(value)
+
+	if err != nil {
+		return wrapError("set", key, err)
+	}
+
+	err = s.pipelined(func(pipe *redis.Pipeline) error {
+		pipe.Set(s.key(key), data, ttl)
+		bump(pipe, ttl, s.key(key))
+		return s.reindex(pipe, []string{key}, nil)
+	})
+
+	return wrapError("set", key, err)
+}
+
+// TTL returns the time left before the key expires
+// or NoExpiration if the key does not expire.
+func (s *Driver) TTL(key string) (time.Duration, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if s.closed {
+		return 0, wrapError("ttl", key, driver.ErrClosed)
+	}
+
+	ttl, err := s.client.PTTL(s.key(key)).Result()
+
+	if err != nil {
+		return 0, wrapError("ttl", key, err)
+	}
+
+	if ttl >= 0 {
+		return ttl, nil
+	}
+
+	// Redis returns -2 for missing keys and -1 for keys
+	// without expiration, the unit differs between versions.
+	ok, err := s.client.Exists(s.key(key)).Result()
+
+	if err != nil {
+		return 0, wrapError("ttl", key, err)
+	}
+
+	if !ok {
+		return 0, wrapError("ttl", key, driver.ErrNotFound)
+	}
+
+	return driver.NoExpiration, nil
+}
+
+// Expire sets the ttl for a existing key. The key is kept in the
+// key index until Range finds that it has expired.
+func (s *Driver) Expire(key string, ttl time.Duration) error {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if s.closed {
+		return wrapError("expire", key, driver.ErrClosed)
+	}
+
+	if ttl <= 0 {
+		return wrapError("expire", key, driver.ErrInvalidArgs)
+	}
+
+	ok, err := s.client.PExpire(s.key(key), ttl).Result()
+
+	if err != nil {
+		return wrapError("expire", key, err)
+	}
+
+	if !ok {
+		return wrapError("expire", key, driver.ErrNotFound)
+	}
+
+	if err := s.client.PExpire(versionKey(s.key(key)), ttl).Err(); err != nil {
+		return wrapError("expire", key, err)
+	}
+
+	return nil
+}
+
+// Persist removes the expiration from a existing key.
+func (s *Driver) Persist(key string) error {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if s.closed {
+		return wrapError("persist", key, driver.ErrClosed)
+	}
+
+	ok, err := s.client.Persist(s.key(key)).Result()
+
+	if err != nil {
+		return wrapError("persist", key, err)
+	}
+
+	if ok {
+		return wrapError("persist", key, s.client.Persist(versionKey(s.key(key))).Err())
+	}
+
+	// Persist returns false both for missing keys
+	// and keys that has no expiration.
+	ok, err = s.client.Exists(s.key(key)).Result()
+
+	if err != nil {
+		return wrapError("persist", key, err)
+	}
+
+	if !ok {
+		return wrapError("persist", key, driver.ErrNotFound)
+	}
+
+	return nil
+}
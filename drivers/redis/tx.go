@@ -0,0 +1,155 @@
+package redis
+
+import (
+	"github.com/frozzare/go-store/driver"
+	"gopkg.in/redis.v5"
+)
+
+// write is a change that is kept in a transaction until it's committed.
+type write struct {
+	data    []byte
+	deleted bool
+}
+
+// tx represents a Redis transaction. Keys that is read is watched
+// and the writes is sent in a MULTI/EXEC block when committed.
+type tx struct {
+	s      *Driver
+	tx     *redis.Tx
+	writes map[string]write
+}
+
+// Exists returns true when a key exists false when not existing in store.
+func (t *tx) Exists(key string) (bool, error) {
+	if w, ok := t.writes[key]; ok {
+		return !w.deleted, nil
+	}
+
+	if err := t.tx.Watch(t.s.key(key)).Err(); err != nil {
+		return false, wrapError("exists", key, err)
+	}
+
+	exists, err := t.tx.Exists(t.s.key(key)).Result()
+
+	if err != nil {
+		return false, wrapError("exists", key, err)
+	}
+
+	return exists, nil
+}
+
+// Get returns the value for a key if any.
+func (t *tx) Get(key string, args ...interface{}) (interface{}, error) {
+	if err := driver.CheckArgs(args); err != nil {
+		return nil, wrapError("get", key, err)
+	}
+
+	var res []byte
+
+	if w, ok := t.writes[key]; ok {
+		if w.deleted {
+			return nil, wrapError("get", key, driver.ErrNotFound)
+		}
+
+		res = w.data
+	} else {
+		if err := t.tx.Watch(t.s.key(key)).Err(); err != nil {
+			return nil, wrapError("get", key, err)
+		}
+
+		var err error
+
+		if res, err = t.tx.Get(t.s.key(key)).Bytes(); err != nil {
+			return nil, wrapError("get", key, err)
+		}
+	}
+
+	value, err := driver.Decode(t.s.codec, res, args)
+
+	if err != nil {
+		return nil, wrapError("get", key, err)
+	}
+
+	return value, nil
+}
+
+// Set key with value in store.
+func (t *tx) Set(key string, value interface{}) error {
+	data, err := t.s.codec.Marshal(value)
+
+	if err != nil {
+		return wrapError("set", key, err)
+	}
+
+	t.writes[key] = write{data: data}
+
+	return nil
+}
+
+// Delete key from store.
+func (t *tx) Delete(key string) error {
+	t.writes[key] = write{deleted: true}
+
+	return nil
+}
+
+// Update runs fn in a Redis transaction. The keys read by fn is
+// watched and the writes is committed with MULTI/EXEC if fn returns
+// nil. ErrConflict is returned if a watched key was changed before
+// the transaction was committed. The keys fn reads and writes can be
+// in any slot and the key index on another server, so ErrNotSupported
+// is returned for a cluster or ring.
+func (s *Driver) Update(fn func(tx driver.Tx) error) error {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if s.closed {
+		return wrapError("update", "", driver.ErrClosed)
+	}
+
+	if s.sharded() {
+		return wrapError("update", "", driver.ErrNotSupported)
+	}
+
+	var fnErr error
+
+	err := s.watch(func(rtx *redis.Tx) error {
+		t := &tx{s: s, tx: rtx, writes: make(map[string]write)}
+
+		if fnErr = fn(t); fnErr != nil {
+			return fnErr
+		}
+
+		if len(t.writes) == 0 {
+			return nil
+		}
+
+		var set, deleted []string
+
+		for key, w := range t.writes {
+			if w.deleted {
+				deleted = append(deleted, key)
+			} else {
+				set = append(set, key)
+			}
+		}
+
+		return s.commit(rtx, func(pipe *redis.Pipeline) {
+			for key, w := range t.writes {
+				if w.deleted {
+					pipe.Del(s.key(key))
+					unversion(pipe, s.key(key))
+				} else {
+					pipe.Set(s.key(key), w.data, 0)
+					bump(pipe, 0, s.key(key))
+				}
+			}
+		}, set, deleted)
+	})
+
+	if fnErr != nil {
+		return fnErr
+	}
+
+	return wrapError("update", "", err)
+}
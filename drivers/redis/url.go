@@ -0,0 +1,104 @@
+package redis
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/frozzare/go-store/driver"
+	"gopkg.in/redis.v5"
+)
+
+// init registers the redis:// url scheme.
+func init() {
+	driver.RegisterURL("redis", openURL)
+}
+
+// parseURL returns the config for a redis://:password@host:port/db
+// url. The query parameters is codec, flush, index, notify,
+// dial_timeout, read_timeout, write_timeout, pool_size and
+// max_retries, where flush=db selects FlushDB, index=true keeps the
+// key index and notify=true lets Watch enable the notifications.
+func parseURL(u *url.URL) (Config, error) {
+	var c Config
+
+	if err := driver.URLParams(u, "codec", "flush", "index", "notify", "dial_timeout", "read_timeout", "write_timeout", "pool_size", "max_retries"); err != nil {
+		return c, err
+	}
+
+	var err error
+
+	if c.Codec, err = driver.URLCodec(u); err != nil {
+		return c, err
+	}
+
+	switch flush := u.Query().Get("flush"); flush {
+	case "", "scan":
+		c.FlushMode = FlushScan
+	case "db":
+		c.FlushMode = FlushDB
+	default:
+		return c, fmt.Errorf("store: invalid redis url flush mode %q: %w", flush, driver.ErrInvalidArgs)
+	}
+
+	if c.Index, err = driver.URLBool(u, "index"); err != nil {
+		return c, err
+	}
+
+	if c.Notify, err = driver.URLBool(u, "notify"); err != nil {
+		return c, err
+	}
+
+	options := &redis.Options{Addr: u.Host}
+	c.Options = options
+
+	if options.Addr == "" {
+		options.Addr = "localhost:6379"
+	} else if u.Port() == "" {
+		options.Addr += ":6379"
+	}
+
+	if u.User != nil {
+		options.Password, _ = u.User.Password()
+	}
+
+	if db := strings.Trim(u.Path, "/"); db != "" {
+		if options.DB, err = strconv.Atoi(db); err != nil {
+			return c, fmt.Errorf("store: invalid redis url database %q: %w", db, driver.ErrInvalidArgs)
+		}
+	}
+
+	if options.DialTimeout, err = driver.URLDuration(u, "dial_timeout"); err != nil {
+		return c, err
+	}
+
+	if options.ReadTimeout, err = driver.URLDuration(u, "read_timeout"); err != nil {
+		return c, err
+	}
+
+	if options.WriteTimeout, err = driver.URLDuration(u, "write_timeout"); err != nil {
+		return c, err
+	}
+
+	if options.PoolSize, err = driver.URLInt(u, "pool_size"); err != nil {
+		return c, err
+	}
+
+	if options.MaxRetries, err = driver.URLInt(u, "max_retries"); err != nil {
+		return c, err
+	}
+
+	return c, nil
+}
+
+// openURL opens a store from a redis:// url.
+func openURL(u *url.URL) (driver.Driver, error) {
+	config, err := parseURL(u)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return Open(config)
+}
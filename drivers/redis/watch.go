@@ -0,0 +1,151 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/frozzare/go-store/driver"
+	"gopkg.in/redis.v5"
+)
+
+// notifyFlags is the keyspace notifications Watch needs: keyspace
+// events for generic commands, string commands, expired and evicted keys.
+const notifyFlags = "Kg$xe"
+
+// events maps keyspace notifications to event types,
+// other notifications is ignored.
+var events = map[string]driver.EventType{
+	"set":         driver.EventSet,
+	"incrby":      driver.EventSet,
+	"incrbyfloat": driver.EventSet,
+	"del":         driver.EventDelete,
+	"evicted":     driver.EventDelete,
+	"expired":     driver.EventExpire,
+}
+
+// notify checks that the server has the keyspace notifications Watch
+// needs enabled, they is enabled with CONFIG SET if enable is set and
+// a error is returned if not. Managed Redis services often disables
+// CONFIG, the notifications is then expected to be enabled.
+func notify(client *redis.Client, enable bool) error {
+	res, err := client.ConfigGet("notify-keyspace-events").Result()
+
+	if isUnknown(err) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	flags := ""
+
+	if len(res) > 1 {
+		flags, _ = res[1].(string)
+	}
+
+	value := flags
+
+	for _, c := range notifyFlags {
+		if strings.ContainsRune(value, c) || c != 'K' && strings.ContainsRune(value, 'A') {
+			continue
+		}
+
+		value += string(c)
+	}
+
+	if value == flags {
+		return nil
+	}
+
+	if !enable {
+		return fmt.Errorf("store: redis notify-keyspace-events is %q and must include %q for Watch, set it on the server or use WithNotify: %w", flags, notifyFlags, driver.ErrNotSupported)
+	}
+
+	return client.ConfigSet("notify-keyspace-events", value).Err()
+}
+
+// Watch returns a channel that receives events for keys with the
+// prefix until the context is done. The events comes from Redis
+// keyspace notifications, which must be enabled on the server or
+// with CONFIG SET by setting Config.Notify. Redis does not retry the
+// notifications, so the channel is also closed if the connection
+// is lost. The notifications is only sent to clients of the server
+// the key is on, so ErrNotSupported is returned for a cluster or ring.
+func (s *Driver) Watch(ctx context.Context, prefix string) (<-chan driver.Event, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if s.closed {
+		return nil, wrapError("watch", prefix, driver.ErrClosed)
+	}
+
+	client, ok := s.client.(*redis.Client)
+
+	if !ok {
+		return nil, wrapError("watch", prefix, driver.ErrNotSupported)
+	}
+
+	if err := notify(client, s.notify); err != nil {
+		return nil, wrapError("watch", prefix, err)
+	}
+
+	channel := fmt.Sprintf("__keyspace@%d__:", s.db)
+	pubsub, err := client.PSubscribe(channel+escape(s.key(prefix))+"*", s.flushChannel())
+
+	if err != nil {
+		return nil, wrapError("watch", prefix, err)
+	}
+
+	out := make(chan driver.Event, driver.WatchBuffer)
+	done := make(chan struct{})
+
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-done:
+		}
+
+		pubsub.Close()
+	}()
+
+	go func() {
+		defer close(out)
+		defer close(done)
+
+		for {
+			msg, err := pubsub.ReceiveMessage()
+
+			if err != nil {
+				return
+			}
+
+			var e driver.Event
+
+			if msg.Channel == s.flushChannel() {
+				e.Type = driver.EventFlush
+			} else {
+				key := strings.TrimPrefix(msg.Channel, channel)
+
+				if internal(key) {
+					continue
+				}
+
+				e.Key = s.trim(key)
+				e.Type = events[msg.Payload]
+			}
+
+			if e.Type == 0 {
+				continue
+			}
+
+			select {
+			case out <- e:
+			default:
+			}
+		}
+	}()
+
+	return out, nil
+}
@@ -0,0 +1,102 @@
+package rethinkdb
+
+import (
+	"github.com/frozzare/go-store/driver"
+
+	r "gopkg.in/gorethink/gorethink.v3"
+)
+
+// ids returns the keys as a slice that can be passed to GetAll.
+func ids(keys []string) []interface{} {
+	ids := make([]interface{}, len(keys))
+
+	for i, key := range keys {
+		ids[i] = key
+	}
+
+	return ids
+}
+
+// GetMulti returns the values for the keys that exists in store.
+func (s *Driver) GetMulti(keys []string, args ...interface{}) (map[string]interface{}, error) {
+	if err := driver.CheckArgs(args); err != nil {
+		return nil, wrapError("getmulti", "", err)
+	}
+
+	found := make(map[string]interface{}, len(keys))
+
+	if len(keys) > 0 {
+		res, err := r.Table(s.table).GetAll(ids(keys)...).Filter(alive).Run(s.session)
+
+		if err != nil {
+			return nil, wrapError("getmulti", "", err)
+		}
+
+		defer res.Close()
+
+		var rows []map[string]interface{}
+
+		if err := res.All(&rows); err != nil {
+			return nil, wrapError("getmulti", "", err)
+		}
+
+		for _, row := range rows {
+			found[row["id"].(string)] = row["value"]
+		}
+	}
+
+	values, err := driver.DecodeMulti(keys, args, func(key string, v interface{}) error {
+		value, ok := found[key]
+
+		if !ok {
+			return driver.ErrNotFound
+		}
+
+		_, err := s.decode(value, []interface{}{v})
+
+		return err
+	})
+
+	if err != nil {
+		return nil, wrapError("getmulti", "", err)
+	}
+
+	return values, nil
+}
+
+// SetMulti keys with values in store.
+func (s *Driver) SetMulti(values map[string]interface{}) error {
+	if len(values) == 0 {
+		return nil
+	}
+
+	docs := make([]map[string]interface{}, 0, len(values))
+
+	for key, value := range values {
+		data, err := s.codec.Marshal(value)
+
+		if err != nil {
+			return wrapError("setmulti", key, err)
+		}
+
+		docs = append(docs, map[string]interface{}{
+			"id":    key,
+			"value": data,
+		})
+	}
+
+	_, err := s.put(docs...).RunWrite(s.session)
+
+	return wrapError("setmulti", "", err)
+}
+
+// DeleteMulti keys from store.
+func (s *Driver) DeleteMulti(keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	_, err := r.Table(s.table).GetAll(ids(keys)...).Delete().RunWrite(s.session)
+
+	return wrapError("deletemulti", "", err)
+}
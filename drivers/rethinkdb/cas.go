@@ -0,0 +1,146 @@
+package rethinkdb
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/frozzare/go-store/driver"
+
+	r "gopkg.in/gorethink/gorethink.v3"
+)
+
+// next returns the version of a document that replaces old. It's
+// the time in microseconds when the document is written or one more
+// than the version of old if that is later, so a key that is deleted
+// and written again does not get back a earlier version.
+func next(old r.Term) r.Term {
+	now := r.Now().ToEpochTime().Mul(1000000).Floor()
+	prev := r.Branch(old.Eq(nil), 0, old.Field("version").Default(0)).Add(1)
+
+	return r.Branch(prev.Gt(now), prev, now)
+}
+
+// versioned returns doc with the next version of old.
+func versioned(old r.Term, doc map[string]interface{}) r.Term {
+	return r.Expr(doc).Merge(map[string]interface{}{"version": next(old)})
+}
+
+// put replaces the documents, each is given the next version
+// of the document with the same id in the same replace.
+func (s *Driver) put(docs ...map[string]interface{}) r.Term {
+	return r.Expr(docs).ForEach(func(doc r.Term) r.Term {
+		return r.Table(s.table).Get(doc.Field("id")).Replace(func(old r.Term) r.Term {
+			return doc.Merge(map[string]interface{}{"version": next(old)})
+		})
+	})
+}
+
+// version returns the version of a document, a document written before
+// the versions was stored has the version 0 until it's written again.
+func version(row map[string]interface{}) (float64, string) {
+	v, _ := row["version"].(float64)
+
+	return v, strconv.FormatInt(int64(v), 36)
+}
+
+// row returns the document for a key or nil if it does not exist.
+func (s *Driver) row(key string) (map[string]interface{}, error) {
+	res, err := r.Table(s.table).Get(key).Run(s.session)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer res.Close()
+
+	var row map[string]interface{}
+
+	if err := res.One(&row); err != nil && err != r.ErrEmptyResult {
+		return nil, err
+	}
+
+	if row == nil || expired(row, time.Now()) {
+		return nil, nil
+	}
+
+	return row, nil
+}
+
+// GetWithVersion returns the value and version for a key.
+func (s *Driver) GetWithVersion(key string, args ...interface{}) (interface{}, string, error) {
+	if err := driver.CheckArgs(args); err != nil {
+		return nil, "", wrapError("get", key, err)
+	}
+
+	row, err := s.row(key)
+
+	if err != nil {
+		return nil, "", wrapError("get", key, err)
+	}
+
+	if row == nil {
+		return nil, "", wrapError("get", key, driver.ErrNotFound)
+	}
+
+	_, v := version(row)
+
+	value, err := s.decode(row["value"], args)
+
+	if err != nil {
+		return nil, "", wrapError("get", key, err)
+	}
+
+	return value, v, nil
+}
+
+// CompareAndSet sets key to value if the version of the key is the
+// given version. The document is replaced with a conditional replace
+// that fails if the document was changed after it was read.
+func (s *Driver) CompareAndSet(key, ver string, value interface{}) error {
+	data, err := s.codec.Marshal(value)
+
+	if err != nil {
+		return wrapError("cas", key, err)
+	}
+
+	row, err := s.row(key)
+
+	if err != nil {
+		return wrapError("cas", key, err)
+	}
+
+	number, current := 0.0, ""
+
+	if row != nil {
+		number, current = version(row)
+	}
+
+	if current != ver {
+		return wrapError("cas", key, driver.ErrConflict)
+	}
+
+	doc := map[string]interface{}{
+		"id":    key,
+		"value": data,
+	}
+
+	// The document must have the version it had when it was read.
+	unchanged := func(old r.Term) r.Term {
+		if row == nil {
+			return old.Eq(nil).Or(alive(old).Not())
+		}
+
+		return old.Ne(nil).And(alive(old)).And(old.Field("version").Default(0).Eq(number))
+	}
+
+	res, err := r.Table(s.table).Get(key).Replace(func(old r.Term) r.Term {
+		return r.Branch(unchanged(old), versioned(old, doc), r.Error(driver.ErrConflict.Error()))
+	}).RunWrite(s.session)
+
+	if res.Errors > 0 && strings.Contains(res.FirstError, driver.ErrConflict.Error()) {
+		return wrapError("cas", key, driver.ErrConflict)
+	}
+
+	return wrapError("cas", key, err)
+}
@@ -0,0 +1,122 @@
+package rethinkdb
+
+import (
+	"github.com/frozzare/go-store/codec"
+	"github.com/frozzare/go-store/driver"
+
+	r "gopkg.in/gorethink/gorethink.v3"
+)
+
+// Config is the configuration of a RethinkDB store.
+type Config struct {
+	// Options is used to connect, localhost:28015 and
+	// the database store by default.
+	Options r.ConnectOpts
+
+	// Session is used instead of connecting with Options if set,
+	// the database is then the default database of the session.
+	Session *r.Session
+
+	// Table is the table the keys is stored in, store by default.
+	Table string
+
+	// Codec encodes the values, codec.JSON by default.
+	Codec driver.Codec
+}
+
+// Option configures a RethinkDB store.
+type Option func(*Config)
+
+// WithConnectOpts sets the options used to connect.
+func WithConnectOpts(options r.ConnectOpts) Option {
+	return func(c *Config) {
+		c.Options = options
+	}
+}
+
+// WithSession sets a existing session to use.
+func WithSession(session *r.Session) Option {
+	return func(c *Config) {
+		c.Session = session
+	}
+}
+
+// WithTable sets the table the keys is stored in.
+func WithTable(table string) Option {
+	return func(c *Config) {
+		c.Table = table
+	}
+}
+
+// newConfig returns the config for the Open args. The args is a
+// Config, Options, a driver.Codec or the positional r.ConnectOpts
+// and table where a nil value keeps the default.
+func newConfig(args []interface{}) (Config, error) {
+	var c Config
+	var i int
+
+	for _, arg := range args {
+		switch v := arg.(type) {
+		case Option:
+			v(&c)
+			continue
+		case Config:
+			c = v
+			continue
+		case *Config:
+			if v != nil {
+				c = *v
+			}
+			continue
+		case driver.Codec:
+			c.Codec = v
+			continue
+		}
+
+		if arg != nil {
+			switch i {
+			case 0:
+				switch v := arg.(type) {
+				case r.ConnectOpts:
+					c.Options = v
+				case *r.ConnectOpts:
+					c.Options = *v
+				case *r.Session:
+					c.Session = v
+				default:
+					return c, driver.ArgError("rethinkdb", i, "a r.ConnectOpts", arg)
+				}
+			case 1:
+				table, ok := arg.(string)
+
+				if !ok {
+					return c, driver.ArgError("rethinkdb", i, "a string table", arg)
+				}
+
+				c.Table = table
+			default:
+				return c, driver.ArgError("rethinkdb", i, "a Option", arg)
+			}
+		}
+
+		i++
+	}
+
+	if c.Options.Address == "" && len(c.Options.Addresses) == 0 {
+		c.Options.Address = "localhost:28015"
+	}
+
+	if c.Options.Database == "" {
+		c.Options.Database = "store"
+	}
+
+	if c.Table == "" {
+		c.Table = "store"
+	}
+
+	if c.Codec == nil {
+		c.Codec = codec.JSON{}
+	}
+
+	return c, nil
+}
@@ -0,0 +1,69 @@
+package rethinkdb
+
+import (
+	"github.com/frozzare/go-store/driver"
+
+	r "gopkg.in/gorethink/gorethink.v3"
+)
+
+// add adds delta to the value of key with a single replace and returns
+// the new value. The sum is stored as a native number that Get decodes
+// like values written before codecs was added, a binary value is
+// coerced to a number first so values written by Set can be counted.
+//
+// RethinkDB numbers are float64, so integers beyond 2^53 lose precision.
+func (s *Driver) add(key string, delta interface{}) (float64, error) {
+	res, err := r.Table(s.table).Get(key).Replace(func(row r.Term) r.Term {
+		value := row.Field("value")
+		number := r.Branch(
+			value.TypeOf().Eq("PTYPE<BINARY>"),
+			value.CoerceTo("string").CoerceTo("number"),
+			value,
+		)
+
+		return r.Branch(
+			row.Eq(nil).Or(alive(row).Not()),
+			map[string]interface{}{"id": key, "value": delta, "version": next(row)},
+			row.Merge(map[string]interface{}{"value": number.Add(delta), "version": next(row)}),
+		)
+	}, r.ReplaceOpts{ReturnChanges: "always"}).RunWrite(s.session)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if len(res.Changes) == 0 {
+		return 0, driver.ErrNotFound
+	}
+
+	doc, _ := res.Changes[0].NewValue.(map[string]interface{})
+	n, ok := doc["value"].(float64)
+
+	if !ok {
+		return 0, driver.ErrInvalidArgs
+	}
+
+	return n, nil
+}
+
+// IncrBy adds delta to the integer value of key and returns the new value.
+func (s *Driver) IncrBy(key string, delta int64) (int64, error) {
+	n, err := s.add(key, delta)
+
+	if err != nil {
+		return 0, wrapError("incr", key, err)
+	}
+
+	return int64(n), nil
+}
+
+// IncrByFloat adds delta to the float value of key and returns the new value.
+func (s *Driver) IncrByFloat(key string, delta float64) (float64, error) {
+	n, err := s.add(key, delta)
+
+	if err != nil {
+		return 0, wrapError("incr", key, err)
+	}
+
+	return n, nil
+}
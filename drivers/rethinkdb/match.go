@@ -0,0 +1,35 @@
+package rethinkdb
+
+import "github.com/frozzare/go-store/driver"
+
+// match returns the keys that matches the pattern. The keys with the
+// literal prefix of the pattern is matched against the pattern.
+func (s *Driver) match(op, pattern string) ([]string, error) {
+	prefixed, err := s.between(driver.MatchPrefix(pattern), "", 0)
+
+	if err != nil {
+		return nil, wrapError(op, "", err)
+	}
+
+	var keys []string
+
+	for _, key := range prefixed {
+		if driver.Match(pattern, key) {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys, nil
+}
+
+// KeysMatch returns the keys that matches the pattern.
+func (s *Driver) KeysMatch(pattern string) ([]string, error) {
+	return s.match("keys", pattern)
+}
+
+// CountMatch returns the number of keys that matches the pattern.
+func (s *Driver) CountMatch(pattern string) (int64, error) {
+	keys, err := s.match("count", pattern)
+
+	return int64(len(keys)), err
+}
@@ -0,0 +1,52 @@
+package rethinkdb
+
+import (
+	"github.com/frozzare/go-store/driver"
+)
+
+// Namespace returns a driver that stores the keys in its own table
+// named after the table of this driver and the name. The table is
+// created if it does not exist. The session is shared and only
+// closed by the driver that opened it, which also stops the janitors
+// of its namespaces.
+func (s *Driver) Namespace(name string) (driver.Driver, error) {
+	if name == "" {
+		return nil, wrapError("namespace", "", driver.ErrInvalidArgs)
+	}
+
+	table := s.table + "_" + name
+
+	if err := createTable(s.session, table); err != nil {
+		return nil, wrapError("namespace", "", err)
+	}
+
+	root := s
+	if s.root != nil {
+		root = s.root
+	}
+
+	ns := &Driver{codec: s.codec, session: s.session, table: table, root: root}
+	ns.janitor = root.namespaceJanitor(ns)
+
+	return ns, nil
+}
+
+// namespaceJanitor returns the janitor for the table of the namespace,
+// namespaces with the same table shares the janitor.
+func (s *Driver) namespaceJanitor(ns *Driver) *driver.Janitor {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if j, ok := s.janitors[ns.table]; ok {
+		return j
+	}
+
+	if s.janitors == nil {
+		s.janitors = make(map[string]*driver.Janitor)
+	}
+
+	j := &driver.Janitor{Purge: ns.purge}
+	s.janitors[ns.table] = j
+
+	return j
+}
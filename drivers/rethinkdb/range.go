@@ -0,0 +1,59 @@
+package rethinkdb
+
+import (
+	"github.com/frozzare/go-store/driver"
+
+	r "gopkg.in/gorethink/gorethink.v3"
+)
+
+// Range returns up to limit keys and values where start <= key < end
+// using Between and OrderBy on the primary key.
+func (s *Driver) Range(start, end string, limit int, reverse bool) ([]driver.KV, error) {
+	var upper interface{} = r.MaxVal
+	if end != "" {
+		upper = end
+	}
+
+	order := r.Asc("id")
+	if reverse {
+		order = r.Desc("id")
+	}
+
+	q := r.Table(s.table).Between(start, upper, r.BetweenOpts{
+		LeftBound:  "closed",
+		RightBound: "open",
+	}).OrderBy(r.OrderByOpts{Index: order}).Filter(alive)
+
+	if limit > 0 {
+		q = q.Limit(limit)
+	}
+
+	res, err := q.Run(s.session)
+
+	if err != nil {
+		return nil, wrapError("range", "", err)
+	}
+
+	defer res.Close()
+
+	var rows []map[string]interface{}
+
+	if err := res.All(&rows); err != nil {
+		return nil, wrapError("range", "", err)
+	}
+
+	pairs := make([]driver.KV, 0, len(rows))
+
+	for _, row := range rows {
+		key := row["id"].(string)
+		value, err := s.decode(row["value"], nil)
+
+		if err != nil {
+			return nil, wrapError("range", key, err)
+		}
+
+		pairs = append(pairs, driver.KV{Key: key, Value: value})
+	}
+
+	return pairs, nil
+}
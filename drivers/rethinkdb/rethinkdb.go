@@ -1,9 +1,13 @@
 package rethinkdb
 
 import (
+	"context"
 	"encoding/json"
-	"math/rand"
+	"errors"
+	"sync"
+	"time"
 
+	"github.com/frozzare/go-store/codec"
 	"github.com/frozzare/go-store/driver"
 
 	r "gopkg.in/gorethink/gorethink.v3"
@@ -11,68 +15,80 @@ import (
 
 // Driver represents a Redis driver.
 type Driver struct {
+	codec   driver.Codec
+	janitor *driver.Janitor
 	session *r.Session
 	table   string
+
+	// root is the driver that owns the session
+	// if this driver is a namespace.
+	root *Driver
+
+	// janitors is the janitors of the namespaces by table,
+	// so Close can stop them. It's guarded by lock.
+	janitors map[string]*driver.Janitor
+	lock     sync.Mutex
 }
 
-// Open creates a new Redis store.
-func Open(args ...interface{}) (driver.Driver, error) {
-	var options r.ConnectOpts
-	var table string
-
-	if len(args) > 0 && args[0] != nil {
-		options = args[0].(r.ConnectOpts)
-	} else {
-		options = r.ConnectOpts{
-			Address:  "localhost:28015",
-			Database: "store",
-		}
-	}
+// init registers the driver with the name rethinkdb.
+func init() {
+	driver.RegisterFactory("rethinkdb", Open)
+}
 
-	session, err := r.Connect(options)
+// Open creates a new RethinkDB store. The args is a Config, Options
+// such as WithTable, a driver.Codec or the positional r.ConnectOpts
+// and table.
+func Open(args ...interface{}) (driver.Driver, error) {
+	config, err := newConfig(args)
 
 	if err != nil {
 		return nil, err
 	}
 
-	if len(args) > 1 {
-		table = args[1].(string)
-	} else {
-		table = "store"
+	session := config.Session
+
+	if session == nil {
+		if session, err = r.Connect(config.Options); err != nil {
+			return nil, err
+		}
+
+		res, _ := r.DBCreate(config.Options.Database).Run(session)
+
+		defer res.Close()
 	}
 
-	res, _ := r.DBCreate(options.Database).Run(session)
+	if err := createTable(session, config.Table); err != nil {
+		return nil, err
+	}
 
-	defer res.Close()
+	s := &Driver{codec: config.Codec, session: session, table: config.Table}
+	s.janitor = &driver.Janitor{Purge: s.purge}
 
-	res, err = r.TableList().Run(session)
+	return s, nil
+}
 
-	defer res.Close()
+// createTable creates the table if it does not exist.
+func createTable(session *r.Session, table string) error {
+	res, err := r.TableList().Run(session)
 
 	if err != nil {
-		return nil, err
+		return err
 	}
 
+	defer res.Close()
+
 	var existing []string
 	res.All(&existing)
 
-	var found bool
 	for _, item := range existing {
 		if item == table {
-			found = true
-			break
+			return nil
 		}
 	}
 
-	if !found {
-		_, err = r.TableCreate(table).Run(session)
-	}
-
-	if err != nil {
-		return nil, err
-	}
+	_, err = r.TableCreate(table).Run(session)
 
-	return &Driver{session: session, table: table}, nil
+	return err
 }
 
 // Open creates a new Redis store with a specified instance.
@@ -80,84 +96,153 @@ func (s *Driver) Open(args ...interface{}) (driver.Driver, error) {
 	return Open(args...)
 }
 
+// Capabilities returns the optional features the driver supports.
+func (s *Driver) Capabilities() driver.Capabilities {
+	return driver.CapContext | driver.CapTTL | driver.CapBatch | driver.CapTx |
+		driver.CapScan | driver.CapMatch | driver.CapRange | driver.CapCAS |
+		driver.CapCounter | driver.CapConditional | driver.CapWatch | driver.CapNamespace
+}
+
+// wrapError maps RethinkDB errors onto the driver errors
+// and records the operation and key.
+func wrapError(op, key string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	e := &driver.Error{Op: op, Key: key, Driver: "rethinkdb", Err: err}
+
+	switch err {
+	case r.ErrEmptyResult:
+		e.Err = driver.ErrNotFound
+	case r.ErrConnectionClosed:
+		e.Err = driver.ErrClosed
+	case r.ErrNoConnections, r.ErrQueryTimeout:
+		e.Temporary = true
+	}
+
+	return e
+}
+
 // Count returns numbers of keys in store.
 func (s *Driver) Count() (int64, error) {
-	res, err := r.Table(s.table).Count().Run(s.session)
+	return s.CountContext(context.Background())
+}
 
-	defer res.Close()
+// CountContext returns numbers of keys in store.
+func (s *Driver) CountContext(ctx context.Context) (int64, error) {
+	res, err := r.Table(s.table).Filter(alive).Count().Run(s.session, r.RunOpts{Context: ctx})
 
 	if err != nil {
-		return 0, err
+		return 0, wrapError("count", "", err)
 	}
 
-	var rows []int
+	defer res.Close()
+
+	var count int64
 
-	res.All(&rows)
+	if err := res.One(&count); err != nil {
+		return 0, wrapError("count", "", err)
+	}
 
-	return int64(rows[rand.Intn(len(rows))]), nil
+	return count, nil
 }
 
 // Exists returns true when a key exists false when not existing in store.
 func (s *Driver) Exists(key string) (bool, error) {
-	res, err := s.Get(key)
+	return s.ExistsContext(context.Background(), key)
+}
+
+// ExistsContext returns true when a key exists false when not existing in store.
+func (s *Driver) ExistsContext(ctx context.Context, key string) (bool, error) {
+	_, err := s.GetContext(ctx, key)
+
+	if errors.Is(err, driver.ErrNotFound) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
 
-	return res != nil && err == nil, err
+	return true, nil
 }
 
 // Get returns the value for a key if any.
 func (s *Driver) Get(key string, args ...interface{}) (interface{}, error) {
-	res, err := r.Table(s.table).Get(key).Run(s.session)
+	return s.GetContext(context.Background(), key, args...)
+}
 
-	defer res.Close()
+// GetContext returns the value for a key if any.
+func (s *Driver) GetContext(ctx context.Context, key string, args ...interface{}) (interface{}, error) {
+	if err := driver.CheckArgs(args); err != nil {
+		return nil, wrapError("get", key, err)
+	}
+
+	res, err := r.Table(s.table).Get(key).Run(s.session, r.RunOpts{Context: ctx})
 
 	if err != nil {
-		return nil, err
+		return nil, wrapError("get", key, err)
 	}
 
-	var row interface{}
+	defer res.Close()
+
+	var row map[string]interface{}
 	err = res.One(&row)
 
 	if err != nil {
-		return nil, err
+		return nil, wrapError("get", key, err)
 	}
 
-	value := row.(map[string]interface{})["value"]
-
-	if len(args) == 0 {
-		return value, nil
+	if expired(row, time.Now()) {
+		return nil, wrapError("get", key, driver.ErrNotFound)
 	}
 
-	j, err := json.Marshal(value)
+	value, err := s.decode(row["value"], args)
 
 	if err != nil {
-		return nil, err
+		return nil, wrapError("get", key, err)
+	}
+
+	return value, nil
+}
+
+// decode decodes a stored value with the codec. Values is stored
+// as binary data, but documents written before codecs was added
+// holds the native value that is decoded as JSON.
+func (s *Driver) decode(value interface{}, args []interface{}) (interface{}, error) {
+	if data, ok := value.([]byte); ok {
+		return driver.Decode(s.codec, data, args)
 	}
 
-	if err := json.Unmarshal(j, &args[0]); err != nil {
+	data, err := json.Marshal(value)
+
+	if err != nil {
 		return nil, err
 	}
 
-	return nil, nil
+	return driver.Decode(codec.JSON{}, data, args)
 }
 
 // Keys returns a string slice with all keys.
 func (s *Driver) Keys() ([]string, error) {
-	res, err := r.Table(s.table).Run(s.session)
+	return s.KeysContext(context.Background())
+}
 
-	defer res.Close()
+// KeysContext returns a string slice with all keys.
+func (s *Driver) KeysContext(ctx context.Context) ([]string, error) {
+	res, err := r.Table(s.table).Filter(alive).Field("id").Run(s.session, r.RunOpts{Context: ctx})
 
 	if err != nil {
-		return []string{}, nil
+		return []string{}, wrapError("keys", "", err)
 	}
 
-	var rows []map[string]interface{}
-
-	res.All(&rows)
+	defer res.Close()
 
 	var keys []string
 
-	for _, row := range rows {
-		keys = append(keys, row["id"].(string))
+	if err := res.All(&keys); err != nil {
+		return []string{}, wrapError("keys", "", err)
 	}
 
 	return keys, nil
@@ -165,15 +250,24 @@ func (s *Driver) Keys() ([]string, error) {
 
 // Set key with value in store.
 func (s *Driver) Set(key string, value interface{}) error {
-	_, err := r.Table(s.table).Insert(map[string]interface{}{
+	return s.SetContext(context.Background(), key, value)
+}
+
+// SetContext key with value in store.
+func (s *Driver) SetContext(ctx context.Context, key string, value interface{}) error {
+	data, err := s.codec.Marshal(value)
+
+	if err != nil {
+		return wrapError("set", key, err)
+	}
+
+	_, err = s.put(map[string]interface{}{
 		"id":    key,
-		"value": value,
-	}, r.InsertOpts{
-		Conflict: "replace",
-	}).RunWrite(s.session)
+		"value": data,
+	}).RunWrite(s.session, r.RunOpts{Context: ctx})
 
 	if err != nil {
-		return err
+		return wrapError("set", key, err)
 	}
 
 	return nil
@@ -181,28 +275,51 @@ func (s *Driver) Set(key string, value interface{}) error {
 
 // Delete key from store.
 func (s *Driver) Delete(key string) error {
-	_, err := r.Table(s.table).Get(key).Delete().RunWrite(s.session)
+	return s.DeleteContext(context.Background(), key)
+}
+
+// DeleteContext key from store.
+func (s *Driver) DeleteContext(ctx context.Context, key string) error {
+	_, err := r.Table(s.table).Get(key).Delete().RunWrite(s.session, r.RunOpts{Context: ctx})
 
 	if err != nil {
-		return err
+		return wrapError("delete", key, err)
 	}
 
 	return nil
 }
 
-// Close will close the RethinkDB session.
+// Close will close the RethinkDB session. A namespace does not close
+// the session since it's owned by the driver it's created from, but
+// the janitors of the namespaces is stopped with the session.
 func (s *Driver) Close() error {
-	s.session.Close()
+	s.janitor.Stop()
+
+	if s.root == nil {
+		s.lock.Lock()
+		defer s.lock.Unlock()
+
+		for _, j := range s.janitors {
+			j.Stop()
+		}
+
+		s.session.Close()
+	}
 
 	return nil
 }
 
 // Flush will remove all keys and values from the store.
 func (s *Driver) Flush() error {
-	_, err := r.Table(s.table).Delete().RunWrite(s.session)
+	return s.FlushContext(context.Background())
+}
+
+// FlushContext will remove all keys and values from the store.
+func (s *Driver) FlushContext(ctx context.Context) error {
+	_, err := r.Table(s.table).Delete().RunWrite(s.session, r.RunOpts{Context: ctx})
 
 	if err != nil {
-		return err
+		return wrapError("flush", "", err)
 	}
 
 	return nil
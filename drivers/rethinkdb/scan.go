@@ -0,0 +1,68 @@
+package rethinkdb
+
+import (
+	"unicode/utf8"
+
+	r "gopkg.in/gorethink/gorethink.v3"
+)
+
+// between returns up to limit sorted keys with the prefix that is
+// greater than the cursor using Between on the primary key.
+func (s *Driver) between(prefix, cursor string, limit int) ([]string, error) {
+	var lower, upper interface{} = prefix, r.MaxVal
+	opts := r.BetweenOpts{LeftBound: "closed", RightBound: "closed"}
+
+	if cursor > prefix {
+		lower = cursor
+		opts.LeftBound = "open"
+	}
+
+	if prefix != "" {
+		upper = prefix + string(utf8.MaxRune)
+	}
+
+	q := r.Table(s.table).Between(lower, upper, opts).OrderBy(r.OrderByOpts{Index: "id"}).Filter(alive)
+
+	if limit > 0 {
+		q = q.Limit(limit)
+	}
+
+	res, err := q.Field("id").Run(s.session)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer res.Close()
+
+	var keys []string
+
+	if err := res.All(&keys); err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// Scan returns up to limit keys with the prefix starting at the cursor.
+// The keys is sorted, so the cursor is the last key that was returned.
+func (s *Driver) Scan(prefix, cursor string, limit int) ([]string, string, error) {
+	// One more key than the limit is read to know
+	// if there is more keys left.
+	n := limit
+	if n > 0 {
+		n++
+	}
+
+	keys, err := s.between(prefix, cursor, n)
+
+	if err != nil {
+		return nil, "", wrapError("scan", "", err)
+	}
+
+	if limit > 0 && len(keys) > limit {
+		return keys[:limit], keys[limit-1], nil
+	}
+
+	return keys, "", nil
+}
@@ -0,0 +1,92 @@
+package rethinkdb
+
+import (
+	"errors"
+	"strings"
+
+	r "gopkg.in/gorethink/gorethink.v3"
+)
+
+// errSkipped is raised by the replace queries when the
+// condition does not hold and the document is kept.
+var errSkipped = errors.New("rethinkdb: write skipped")
+
+// replaceIf replaces the document of key with doc if the existence
+// of the key is the same as present and reports if it was replaced.
+func (s *Driver) replaceIf(key string, doc map[string]interface{}, present bool) (bool, error) {
+	res, err := r.Table(s.table).Get(key).Replace(func(old r.Term) r.Term {
+		exists := old.Ne(nil).And(alive(old))
+
+		if !present {
+			exists = exists.Not()
+		}
+
+		return r.Branch(exists, versioned(old, doc), r.Error(errSkipped.Error()))
+	}).RunWrite(s.session)
+
+	if res.Errors > 0 && strings.Contains(res.FirstError, errSkipped.Error()) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// SetIfAbsent sets key to value if the key does not exist. The
+// document is inserted with conflict "error", a document that has
+// expired but not yet been purged is replaced conditionally.
+func (s *Driver) SetIfAbsent(key string, value interface{}) (bool, error) {
+	data, err := s.codec.Marshal(value)
+
+	if err != nil {
+		return false, wrapError("set", key, err)
+	}
+
+	doc := map[string]interface{}{
+		"id":    key,
+		"value": data,
+	}
+
+	res, err := r.Table(s.table).Insert(versioned(r.Expr(nil), doc), r.InsertOpts{
+		Conflict: "error",
+	}).RunWrite(s.session)
+
+	if err == nil && res.Inserted > 0 {
+		return true, nil
+	}
+
+	if res.Errors == 0 {
+		return false, wrapError("set", key, err)
+	}
+
+	ok, err := s.replaceIf(key, doc, false)
+
+	if err != nil {
+		return false, wrapError("set", key, err)
+	}
+
+	return ok, nil
+}
+
+// SetIfPresent sets key to value if the key exist.
+func (s *Driver) SetIfPresent(key string, value interface{}) (bool, error) {
+	data, err := s.codec.Marshal(value)
+
+	if err != nil {
+		return false, wrapError("set", key, err)
+	}
+
+	ok, err := s.replaceIf(key, map[string]interface{}{
+		"id":    key,
+		"value": data,
+	}, true)
+
+	if err != nil {
+		return false, wrapError("set", key, err)
+	}
+
+	return ok, nil
+}
@@ -0,0 +1,154 @@
+package rethinkdb
+
+import (
+	"time"
+
+	"github.com/frozzare/go-store/driver"
+
+	r "gopkg.in/gorethink/gorethink.v3"
+)
+
+// alive filters out documents that has expired.
+func alive(row r.Term) r.Term {
+	return row.HasFields("expires").Not().Or(row.Field("expires").Gt(time.Now()))
+}
+
+// expired reports if the document has expired.
+func expired(row map[string]interface{}, now time.Time) bool {
+	t, ok := row["expires"].(time.Time)
+
+	return ok && !t.After(now)
+}
+
+// purge removes expired documents and reports if there is documents
+// left that will expire. It stops when the session is closed.
+func (s *Driver) purge() bool {
+	_, err := r.Table(s.table).Filter(func(row r.Term) r.Term {
+		return alive(row).Not()
+	}).Delete().RunWrite(s.session)
+
+	if err == r.ErrConnectionClosed {
+		return false
+	}
+
+	if err != nil {
+		return true
+	}
+
+	res, err := r.Table(s.table).HasFields("expires").Count().Run(s.session)
+
+	if err != nil {
+		return true
+	}
+
+	defer res.Close()
+
+	var count int64
+
+	if err := res.One(&count); err != nil {
+		return true
+	}
+
+	return count > 0
+}
+
+// update updates a document that has not expired and
+// returns ErrNotFound when no document was found.
+func (s *Driver) update(key string, value interface{}) error {
+	res, err := r.Table(s.table).GetAll(key).Filter(alive).Update(value).RunWrite(s.session)
+
+	if err != nil {
+		return err
+	}
+
+	if res.Replaced+res.Unchanged == 0 {
+		return driver.ErrNotFound
+	}
+
+	return nil
+}
+
+// SetWithTTL key with value in store that expires after the ttl.
+func (s *Driver) SetWithTTL(key string, value interface{}, ttl time.Duration) error {
+	if ttl <= 0 {
+		return wrapError("set", key, driver.ErrInvalidArgs)
+	}
+
+	data, err := s.codec.Marshal(value)
+
+	if err != nil {
+		return wrapError("set", key, err)
+	}
+
+	_, err = s.put(map[string]interface{}{
+		"id":      key,
+		"value":   data,
+		"expires": time.Now().Add(ttl),
+	}).RunWrite(s.session)
+
+	if err != nil {
+		return wrapError("set", key, err)
+	}
+
+	s.janitor.Start()
+
+	return nil
+}
+
+// TTL returns the time left before the key expires
+// or NoExpiration if the key does not expire.
+func (s *Driver) TTL(key string) (time.Duration, error) {
+	res, err := r.Table(s.table).Get(key).Run(s.session)
+
+	if err != nil {
+		return 0, wrapError("ttl", key, err)
+	}
+
+	defer res.Close()
+
+	var row map[string]interface{}
+
+	if err := res.One(&row); err != nil {
+		return 0, wrapError("ttl", key, err)
+	}
+
+	now := time.Now()
+
+	if expired(row, now) {
+		return 0, wrapError("ttl", key, driver.ErrNotFound)
+	}
+
+	t, ok := row["expires"].(time.Time)
+
+	if !ok {
+		return driver.NoExpiration, nil
+	}
+
+	return t.Sub(now), nil
+}
+
+// Expire sets the ttl for a existing key.
+func (s *Driver) Expire(key string, ttl time.Duration) error {
+	if ttl <= 0 {
+		return wrapError("expire", key, driver.ErrInvalidArgs)
+	}
+
+	err := s.update(key, map[string]interface{}{
+		"expires": time.Now().Add(ttl),
+	})
+
+	if err != nil {
+		return wrapError("expire", key, err)
+	}
+
+	s.janitor.Start()
+
+	return nil
+}
+
+// Persist removes the expiration from a existing key.
+func (s *Driver) Persist(key string) error {
+	return wrapError("persist", key, s.update(key, map[string]interface{}{
+		"expires": r.Literal(),
+	}))
+}
@@ -0,0 +1,122 @@
+package rethinkdb
+
+import (
+	"context"
+
+	"github.com/frozzare/go-store/driver"
+
+	r "gopkg.in/gorethink/gorethink.v3"
+)
+
+// tx represents a emulated RethinkDB transaction. RethinkDB has no
+// transactions over many documents, so the writes is kept in the
+// transaction and written when it's committed.
+type tx struct {
+	s       *Driver
+	sets    map[string][]byte
+	deletes map[string]bool
+}
+
+// Exists returns true when a key exists false when not existing in store.
+func (t *tx) Exists(key string) (bool, error) {
+	if _, ok := t.sets[key]; ok {
+		return true, nil
+	}
+
+	if t.deletes[key] {
+		return false, nil
+	}
+
+	return t.s.ExistsContext(context.Background(), key)
+}
+
+// Get returns the value for a key if any.
+func (t *tx) Get(key string, args ...interface{}) (interface{}, error) {
+	if err := driver.CheckArgs(args); err != nil {
+		return nil, wrapError("get", key, err)
+	}
+
+	if data, ok := t.sets[key]; ok {
+		value, err := driver.Decode(t.s.codec, data, args)
+
+		if err != nil {
+			return nil, wrapError("get", key, err)
+		}
+
+		return value, nil
+	}
+
+	if t.deletes[key] {
+		return nil, wrapError("get", key, driver.ErrNotFound)
+	}
+
+	return t.s.GetContext(context.Background(), key, args...)
+}
+
+// Set key with value in store.
+func (t *tx) Set(key string, value interface{}) error {
+	data, err := t.s.codec.Marshal(value)
+
+	if err != nil {
+		return wrapError("set", key, err)
+	}
+
+	t.sets[key] = data
+	delete(t.deletes, key)
+
+	return nil
+}
+
+// Delete key from store.
+func (t *tx) Delete(key string) error {
+	t.deletes[key] = true
+	delete(t.sets, key)
+
+	return nil
+}
+
+// Update runs fn in a emulated transaction. Nothing is written if fn
+// returns a error, otherwise the writes is sent in one insert and one
+// delete query. RethinkDB only guarantees atomicity for a single
+// document, so other clients can see the keys read by fn change and
+// see a part of the writes before all of them is done.
+func (s *Driver) Update(fn func(tx driver.Tx) error) error {
+	t := &tx{s: s, sets: make(map[string][]byte), deletes: make(map[string]bool)}
+
+	if err := fn(t); err != nil {
+		return err
+	}
+
+	if len(t.sets) > 0 {
+		docs := make([]map[string]interface{}, 0, len(t.sets))
+
+		for key, data := range t.sets {
+			docs = append(docs, map[string]interface{}{
+				"id":    key,
+				"value": data,
+			})
+		}
+
+		_, err := s.put(docs...).RunWrite(s.session)
+
+		if err != nil {
+			return wrapError("update", "", err)
+		}
+	}
+
+	if len(t.deletes) > 0 {
+		keys := make([]string, 0, len(t.deletes))
+
+		for key := range t.deletes {
+			keys = append(keys, key)
+		}
+
+		_, err := r.Table(s.table).GetAll(ids(keys)...).Delete().RunWrite(s.session)
+
+		if err != nil {
+			return wrapError("update", "", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,96 @@
+package rethinkdb
+
+import (
+	"context"
+	"reflect"
+	"time"
+	"unicode/utf8"
+
+	"github.com/frozzare/go-store/driver"
+
+	r "gopkg.in/gorethink/gorethink.v3"
+)
+
+// change is a document in a changefeed.
+type change struct {
+	NewVal map[string]interface{} `gorethink:"new_val"`
+	OldVal map[string]interface{} `gorethink:"old_val"`
+}
+
+// event returns the event for a change, changes that only
+// update the expiration of a document returns false.
+func (c change) event() (driver.Event, bool) {
+	switch {
+	case c.NewVal != nil:
+		if c.OldVal != nil && reflect.DeepEqual(c.OldVal["value"], c.NewVal["value"]) {
+			return driver.Event{}, false
+		}
+
+		key, _ := c.NewVal["id"].(string)
+		return driver.Event{Type: driver.EventSet, Key: key}, true
+	case c.OldVal != nil:
+		key, _ := c.OldVal["id"].(string)
+
+		if expired(c.OldVal, time.Now()) {
+			return driver.Event{Type: driver.EventExpire, Key: key}, true
+		}
+
+		return driver.Event{Type: driver.EventDelete, Key: key}, true
+	}
+
+	return driver.Event{}, false
+}
+
+// Watch returns a channel that receives events for keys with the
+// prefix until the context is done, using a changefeed on the
+// primary key. Flush deletes every document, so it's sent as Delete
+// events, and Expire events is sent when the janitor removes the
+// expired documents.
+func (s *Driver) Watch(ctx context.Context, prefix string) (<-chan driver.Event, error) {
+	q := r.Table(s.table)
+
+	if prefix != "" {
+		q = q.Between(prefix, prefix+string(utf8.MaxRune))
+	}
+
+	res, err := q.Changes().Run(s.session, r.RunOpts{Context: ctx})
+
+	if err != nil {
+		return nil, wrapError("watch", prefix, err)
+	}
+
+	out := make(chan driver.Event, driver.WatchBuffer)
+	done := make(chan struct{})
+
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-done:
+		}
+
+		res.Close()
+	}()
+
+	go func() {
+		defer close(out)
+		defer close(done)
+
+		var c change
+
+		for res.Next(&c) {
+			e, ok := c.event()
+			c = change{}
+
+			if !ok {
+				continue
+			}
+
+			select {
+			case out <- e:
+			default:
+			}
+		}
+	}()
+
+	return out, nil
+}
@@ -0,0 +1,90 @@
+package rwmutex
+
+import (
+	"time"
+
+	"github.com/frozzare/go-store/driver"
+)
+
+// GetMulti returns the values for the keys that exists in store.
+func (s *Driver) GetMulti(keys []string, args ...interface{}) (map[string]interface{}, error) {
+	if err := driver.CheckArgs(args); err != nil {
+		return nil, wrapError("getmulti", "", err)
+	}
+
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if s.closed {
+		return nil, wrapError("getmulti", "", driver.ErrClosed)
+	}
+
+	now := time.Now()
+
+	values, err := driver.DecodeMulti(keys, args, func(key string, v interface{}) error {
+		data, ok := s.data[key]
+
+		if !ok || s.expired(key, now) {
+			return driver.ErrNotFound
+		}
+
+		return driver.Unmarshal(s.codec, data, v)
+	})
+
+	if err != nil {
+		return nil, wrapError("getmulti", "", err)
+	}
+
+	return values, nil
+}
+
+// SetMulti keys with values in store.
+func (s *Driver) SetMulti(values map[string]interface{}) error {
+	data := make(map[string][]byte, len(values))
+
+	for key, value := range values {
+		b, err := s.codec.Marshal(value)
+
+		if err != nil {
+			return wrapError("setmulti", key, err)
+		}
+
+		data[key] = b
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.closed {
+		return wrapError("setmulti", "", driver.ErrClosed)
+	}
+
+	for key, b := range data {
+		s.data[key] = b
+		delete(s.expires, key)
+		s.bump(key)
+		s.hub.Publish(driver.EventSet, key)
+	}
+
+	return nil
+}
+
+// DeleteMulti keys from store.
+func (s *Driver) DeleteMulti(keys ...string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.closed {
+		return wrapError("deletemulti", "", driver.ErrClosed)
+	}
+
+	for _, key := range keys {
+		delete(s.data, key)
+		delete(s.expires, key)
+		delete(s.versions, key)
+	}
+
+	s.hub.Publish(driver.EventDelete, keys...)
+
+	return nil
+}
@@ -0,0 +1,81 @@
+package rwmutex
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/frozzare/go-store/driver"
+)
+
+// version returns the version of a key or a empty string if the
+// key does not exist, it must be called while holding the lock.
+func (s *Driver) version(key string) string {
+	if _, ok := s.data[key]; !ok || s.expired(key, time.Now()) {
+		return ""
+	}
+
+	return strconv.FormatUint(s.versions[key], 36)
+}
+
+// bump sets the version of a key to the next sequence, it must
+// be called while holding the write lock.
+func (s *Driver) bump(key string) {
+	s.sequence++
+	s.versions[key] = s.sequence
+}
+
+// GetWithVersion returns the value and version for a key.
+func (s *Driver) GetWithVersion(key string, args ...interface{}) (interface{}, string, error) {
+	if err := driver.CheckArgs(args); err != nil {
+		return nil, "", wrapError("get", key, err)
+	}
+
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if s.closed {
+		return nil, "", wrapError("get", key, driver.ErrClosed)
+	}
+
+	version := s.version(key)
+
+	if version == "" {
+		return nil, "", wrapError("get", key, driver.ErrNotFound)
+	}
+
+	value, err := driver.Decode(s.codec, s.data[key], args)
+
+	if err != nil {
+		return nil, "", wrapError("get", key, err)
+	}
+
+	return value, version, nil
+}
+
+// CompareAndSet sets key to value if the version of the key
+// is the given version while holding the write lock.
+func (s *Driver) CompareAndSet(key, version string, value interface{}) error {
+	data, err := s.codec.Marshal(value)
+
+	if err != nil {
+		return wrapError("cas", key, err)
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.closed {
+		return wrapError("cas", key, driver.ErrClosed)
+	}
+
+	if s.version(key) != version {
+		return wrapError("cas", key, driver.ErrConflict)
+	}
+
+	s.data[key] = data
+	delete(s.expires, key)
+	s.bump(key)
+	s.hub.Publish(driver.EventSet, key)
+
+	return nil
+}
@@ -0,0 +1,40 @@
+package rwmutex
+
+import (
+	"github.com/frozzare/go-store/codec"
+	"github.com/frozzare/go-store/driver"
+)
+
+// Config is the configuration of a RWMutex store.
+type Config struct {
+	// Codec encodes the values, codec.JSON by default.
+	Codec driver.Codec
+}
+
+// newConfig returns the config for the Open args. The
+// args is a Config or a driver.Codec.
+func newConfig(args []interface{}) (Config, error) {
+	var c Config
+
+	for i, arg := range args {
+		switch v := arg.(type) {
+		case Config:
+			c = v
+		case *Config:
+			if v != nil {
+				c = *v
+			}
+		case driver.Codec:
+			c.Codec = v
+		case nil:
+		default:
+			return c, driver.ArgError("rwmutex", i, "a Config or driver.Codec", arg)
+		}
+	}
+
+	if c.Codec == nil {
+		c.Codec = codec.JSON{}
+	}
+
+	return c, nil
+}
@@ -0,0 +1,58 @@
+package rwmutex
+
+import (
+	"time"
+
+	"github.com/frozzare/go-store/driver"
+)
+
+// modify replaces the data of a key with the data returned by fn
+// while holding the write lock. The data passed to fn is nil if the
+// key does not exist. The ttl of the key is kept.
+func (s *Driver) modify(op, key string, fn func(data []byte) ([]byte, error)) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.closed {
+		return wrapError(op, key, driver.ErrClosed)
+	}
+
+	data, ok := s.data[key]
+
+	if ok && s.expired(key, time.Now()) {
+		data = nil
+		delete(s.expires, key)
+	}
+
+	data, err := fn(data)
+
+	if err != nil {
+		return wrapError(op, key, err)
+	}
+
+	s.data[key] = data
+	s.bump(key)
+	s.hub.Publish(driver.EventSet, key)
+
+	return nil
+}
+
+// IncrBy adds delta to the integer value of key and returns the new value.
+func (s *Driver) IncrBy(key string, delta int64) (n int64, err error) {
+	err = s.modify("incr", key, func(data []byte) (res []byte, err error) {
+		n, res, err = driver.Add(s.codec, data, delta)
+		return
+	})
+
+	return
+}
+
+// IncrByFloat adds delta to the float value of key and returns the new value.
+func (s *Driver) IncrByFloat(key string, delta float64) (n float64, err error) {
+	err = s.modify("incr", key, func(data []byte) (res []byte, err error) {
+		n, res, err = driver.Add(s.codec, data, delta)
+		return
+	})
+
+	return
+}
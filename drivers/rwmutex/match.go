@@ -0,0 +1,40 @@
+package rwmutex
+
+import (
+	"time"
+
+	"github.com/frozzare/go-store/driver"
+)
+
+// match returns the keys that matches the pattern.
+func (s *Driver) match(op, pattern string) ([]string, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if s.closed {
+		return nil, wrapError(op, "", driver.ErrClosed)
+	}
+
+	var keys []string
+	now := time.Now()
+
+	for key := range s.data {
+		if driver.Match(pattern, key) && !s.expired(key, now) {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys, nil
+}
+
+// KeysMatch returns the keys that matches the pattern.
+func (s *Driver) KeysMatch(pattern string) ([]string, error) {
+	return s.match("keys", pattern)
+}
+
+// CountMatch returns the number of keys that matches the pattern.
+func (s *Driver) CountMatch(pattern string) (int64, error) {
+	keys, err := s.match("count", pattern)
+
+	return int64(len(keys)), err
+}
@@ -0,0 +1,52 @@
+package rwmutex
+
+import (
+	"sort"
+	"time"
+
+	"github.com/frozzare/go-store/driver"
+)
+
+// Range returns up to limit keys and values where start <= key < end
+// from a sorted snapshot of the keys.
+func (s *Driver) Range(start, end string, limit int, reverse bool) ([]driver.KV, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if s.closed {
+		return nil, wrapError("range", "", driver.ErrClosed)
+	}
+
+	var keys []string
+	now := time.Now()
+
+	for key := range s.data {
+		if key >= start && (end == "" || key < end) && !s.expired(key, now) {
+			keys = append(keys, key)
+		}
+	}
+
+	if reverse {
+		sort.Sort(sort.Reverse(sort.StringSlice(keys)))
+	} else {
+		sort.Strings(keys)
+	}
+
+	if limit > 0 && len(keys) > limit {
+		keys = keys[:limit]
+	}
+
+	pairs := make([]driver.KV, 0, len(keys))
+
+	for _, key := range keys {
+		value, err := driver.Decode(s.codec, s.data[key], nil)
+
+		if err != nil {
+			return nil, wrapError("range", key, err)
+		}
+
+		pairs = append(pairs, driver.KV{Key: key, Value: value})
+	}
+
+	return pairs, nil
+}
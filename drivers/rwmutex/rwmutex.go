@@ -1,22 +1,54 @@
 package rwmutex
 
 import (
-	"encoding/json"
-	"reflect"
+	"context"
 	"sync"
+	"time"
 
 	"github.com/frozzare/go-store/driver"
 )
 
 // Driver represents a rwmutex driver.
 type Driver struct {
-	lock sync.RWMutex
-	data map[string][]byte
+	lock    sync.RWMutex
+	codec   driver.Codec
+	data    map[string][]byte
+	expires map[string]time.Time
+	janitor *driver.Janitor
+	hub     driver.Hub
+	closed  bool
+
+	// versions is the version of every key, given from sequence on each
+	// write. The sequence is kept when keys is deleted or flushed, so a
+	// key never gets back a earlier version.
+	versions map[string]uint64
+	sequence uint64
+}
+
+// init registers the driver with the name rwmutex.
+func init() {
+	driver.RegisterFactory("rwmutex", Open)
 }
 
-// Open creates a new RWMutex store.
+// Open creates a new RWMutex store. The args
+// is a Config or a driver.Codec.
 func Open(args ...interface{}) (driver.Driver, error) {
-	return &Driver{data: make(map[string][]byte)}, nil
+	config, err := newConfig(args)
+
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Driver{
+		codec:    config.Codec,
+		data:     make(map[string][]byte),
+		expires:  make(map[string]time.Time),
+		versions: make(map[string]uint64),
+	}
+
+	s.janitor = &driver.Janitor{Purge: s.purge}
+
+	return s, nil
 }
 
 // Open creates a new RWMutex store with a specified instance.
@@ -24,60 +56,139 @@ func (s *Driver) Open(args ...interface{}) (driver.Driver, error) {
 	return Open(args...)
 }
 
+// Capabilities returns the optional features the driver supports.
+func (s *Driver) Capabilities() driver.Capabilities {
+	return driver.CapContext | driver.CapTTL | driver.CapBatch | driver.CapTx |
+		driver.CapScan | driver.CapMatch | driver.CapRange | driver.CapCAS |
+		driver.CapCounter | driver.CapConditional | driver.CapWatch
+}
+
+// wrapError records the operation and key for a error.
+func wrapError(op, key string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &driver.Error{Op: op, Key: key, Driver: "rwmutex", Err: err}
+}
+
 // Count returns numbers of keys in store.
 func (s *Driver) Count() (int64, error) {
+	return s.CountContext(context.Background())
+}
+
+// CountContext returns numbers of keys in store.
+func (s *Driver) CountContext(ctx context.Context) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, wrapError("count", "", err)
+	}
+
 	s.lock.RLock()
 	defer s.lock.RUnlock()
-	return int64(len(s.data)), nil
+
+	if s.closed {
+		return 0, wrapError("count", "", driver.ErrClosed)
+	}
+
+	count := int64(len(s.data))
+	now := time.Now()
+
+	for key := range s.expires {
+		if s.expired(key, now) {
+			count--
+		}
+	}
+
+	return count, nil
 }
 
 // Exists returns true when a key exists false when not existing in store.
 func (s *Driver) Exists(key string) (bool, error) {
+	return s.ExistsContext(context.Background(), key)
+}
+
+// ExistsContext returns true when a key exists false when not existing in store.
+func (s *Driver) ExistsContext(ctx context.Context, key string) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, wrapError("exists", key, err)
+	}
+
 	s.lock.RLock()
 	defer s.lock.RUnlock()
 
+	if s.closed {
+		return false, wrapError("exists", key, driver.ErrClosed)
+	}
+
 	_, exists := s.data[key]
 
-	return exists, nil
+	return exists && !s.expired(key, time.Now()), nil
 }
 
 // Get returns the value for a key if any.
 func (s *Driver) Get(key string, args ...interface{}) (interface{}, error) {
+	return s.GetContext(context.Background(), key, args...)
+}
+
+// GetContext returns the value for a key if any.
+func (s *Driver) GetContext(ctx context.Context, key string, args ...interface{}) (interface{}, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, wrapError("get", key, err)
+	}
+
+	if err := driver.CheckArgs(args); err != nil {
+		return nil, wrapError("get", key, err)
+	}
+
 	s.lock.RLock()
 
 	defer s.lock.RUnlock()
 
-	var value interface{}
-
-	if len(args) > 0 {
-		value = args[0]
+	if s.closed {
+		return nil, wrapError("get", key, driver.ErrClosed)
 	}
 
-	if err := json.Unmarshal(s.data[key], &value); err == nil {
-		if len(args) > 0 {
-			return nil, nil
-		}
+	data, ok := s.data[key]
 
-		return value, nil
+	if !ok || s.expired(key, time.Now()) {
+		return nil, wrapError("get", key, driver.ErrNotFound)
 	}
 
-	if len(s.data[key]) == 0 {
-		return nil, nil
+	value, err := driver.Decode(s.codec, data, args)
+
+	if err != nil {
+		return nil, wrapError("get", key, err)
 	}
 
-	return string(s.data[key]), nil
+	return value, nil
 }
 
 // Keys returns a string slice with all keys.
 func (s *Driver) Keys() ([]string, error) {
-	s.lock.Lock()
+	return s.KeysContext(context.Background())
+}
 
-	defer s.lock.Unlock()
+// KeysContext returns a string slice with all keys.
+func (s *Driver) KeysContext(ctx context.Context) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return []string{}, wrapError("keys", "", err)
+	}
+
+	s.lock.RLock()
+
+	defer s.lock.RUnlock()
+
+	if s.closed {
+		return []string{}, wrapError("keys", "", driver.ErrClosed)
+	}
 
 	var keys []string
+	now := time.Now()
 
 	for key := range s.data {
-		keys = append(keys, key)
+		if !s.expired(key, now) {
+			keys = append(keys, key)
+		}
 	}
 
 	return keys, nil
@@ -85,42 +196,96 @@ func (s *Driver) Keys() ([]string, error) {
 
 // Set key with value in store.
 func (s *Driver) Set(key string, value interface{}) error {
-	s.lock.Lock()
+	return s.SetContext(context.Background(), key, value)
+}
 
-	defer s.lock.Unlock()
+// SetContext key with value in store.
+func (s *Driver) SetContext(ctx context.Context, key string, value interface{}) error {
+	if err := ctx.Err(); err != nil {
+		return wrapError("set", key, err)
+	}
 
-	if reflect.TypeOf(value).Kind() != reflect.String {
-		value, err := json.Marshal(value)
+	data, err := s.codec.Marshal(value)
 
-		if err != nil {
-			return err
-		}
+	if err != nil {
+		return wrapError("set", key, err)
+	}
+
+	s.lock.Lock()
 
-		s.data[key] = value
-	} else {
-		s.data[key] = []byte(value.(string))
+	defer s.lock.Unlock()
+
+	if s.closed {
+		return wrapError("set", key, driver.ErrClosed)
 	}
 
+	s.data[key] = data
+	delete(s.expires, key)
+	s.bump(key)
+	s.hub.Publish(driver.EventSet, key)
+
 	return nil
 }
 
 // Delete key from store.
 func (s *Driver) Delete(key string) error {
+	return s.DeleteContext(context.Background(), key)
+}
+
+// DeleteContext key from store.
+func (s *Driver) DeleteContext(ctx context.Context, key string) error {
+	if err := ctx.Err(); err != nil {
+		return wrapError("delete", key, err)
+	}
+
 	s.lock.Lock()
 	defer s.lock.Unlock()
+
+	if s.closed {
+		return wrapError("delete", key, driver.ErrClosed)
+	}
+
 	delete(s.data, key)
+	delete(s.expires, key)
+	delete(s.versions, key)
+	s.hub.Publish(driver.EventDelete, key)
 	return nil
 }
 
-// Close does not exists for RWMutex driver.
+// Close will release the data in the store.
 func (s *Driver) Close() error {
+	s.janitor.Stop()
+	s.hub.Close()
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.data = nil
+	s.expires = nil
+	s.versions = nil
+	s.closed = true
 	return nil
 }
 
 // Flush will remove all keys and values from the store.
 func (s *Driver) Flush() error {
+	return s.FlushContext(context.Background())
+}
+
+// FlushContext will remove all keys and values from the store.
+func (s *Driver) FlushContext(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return wrapError("flush", "", err)
+	}
+
 	s.lock.Lock()
 	defer s.lock.Unlock()
+
+	if s.closed {
+		return wrapError("flush", "", driver.ErrClosed)
+	}
+
 	s.data = make(map[string][]byte)
+	s.expires = make(map[string]time.Time)
+	s.versions = make(map[string]uint64)
+	s.hub.Publish(driver.EventFlush)
 	return nil
 }
@@ -0,0 +1,39 @@
+package rwmutex
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/frozzare/go-store/driver"
+)
+
+// Scan returns up to limit keys with the prefix starting at the cursor.
+// The keys is sorted, so the cursor is the last key that was returned.
+func (s *Driver) Scan(prefix, cursor string, limit int) ([]string, string, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if s.closed {
+		return nil, "", wrapError("scan", "", driver.ErrClosed)
+	}
+
+	var keys []string
+	now := time.Now()
+
+	for key := range s.data {
+		if strings.HasPrefix(key, prefix) && key > cursor && !s.expired(key, now) {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Strings(keys)
+
+	if limit < 1 || len(keys) <= limit {
+		return keys, "", nil
+	}
+
+	keys = keys[:limit]
+
+	return keys, keys[limit-1], nil
+}
@@ -0,0 +1,47 @@
+package rwmutex
+
+import (
+	"time"
+
+	"github.com/frozzare/go-store/driver"
+)
+
+// setIf sets key to value if the existence of the key
+// is the same as present while holding the write lock.
+func (s *Driver) setIf(key string, value interface{}, present bool) (bool, error) {
+	data, err := s.codec.Marshal(value)
+
+	if err != nil {
+		return false, wrapError("set", key, err)
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.closed {
+		return false, wrapError("set", key, driver.ErrClosed)
+	}
+
+	_, ok := s.data[key]
+
+	if exists := ok && !s.expired(key, time.Now()); exists != present {
+		return false, nil
+	}
+
+	s.data[key] = data
+	delete(s.expires, key)
+	s.bump(key)
+	s.hub.Publish(driver.EventSet, key)
+
+	return true, nil
+}
+
+// SetIfAbsent sets key to value if the key does not exist.
+func (s *Driver) SetIfAbsent(key string, value interface{}) (bool, error) {
+	return s.setIf(key, value, false)
+}
+
+// SetIfPresent sets key to value if the key exist.
+func (s *Driver) SetIfPresent(key string, value interface{}) (bool, error) {
+	return s.setIf(key, value, true)
+}
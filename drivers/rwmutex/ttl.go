@@ -0,0 +1,130 @@
+package rwmutex
+
+import (
+	"time"
+
+	"github.com/frozzare/go-store/driver"
+)
+
+// expired reports if the key has expired. The lock must be held.
+func (s *Driver) expired(key string, now time.Time) bool {
+	expires, ok := s.expires[key]
+
+	return ok && !now.Before(expires)
+}
+
+// purge removes expired keys and reports if there is keys left with a ttl.
+func (s *Driver) purge() bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	now := time.Now()
+
+	for key := range s.expires {
+		if s.expired(key, now) {
+			delete(s.data, key)
+			delete(s.expires, key)
+			delete(s.versions, key)
+			s.hub.Publish(driver.EventExpire, key)
+		}
+	}
+
+	return len(s.expires) > 0
+}
+
+// SetWithTTL key with value in store that expires after the ttl.
+func (s *Driver) SetWithTTL(key string, value interface{}, ttl time.Duration) error {
+	if ttl <= 0 {
+		return wrapError("set", key, driver.ErrInvalidArgs)
+	}
+
+	data, err := s.codec.Marshal(value)
+
+	if err != nil {
+		return wrapError("set", key, err)
+	}
+
+	s.lock.Lock()
+
+	defer s.lock.Unlock()
+
+	if s.closed {
+		return wrapError("set", key, driver.ErrClosed)
+	}
+
+	s.data[key] = data
+	s.expires[key] = time.Now().Add(ttl)
+	s.bump(key)
+	s.hub.Publish(driver.EventSet, key)
+	s.janitor.Start()
+
+	return nil
+}
+
+// TTL returns the time left before the key expires
+// or NoExpiration if the key does not expire.
+func (s *Driver) TTL(key string) (time.Duration, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if s.closed {
+		return 0, wrapError("ttl", key, driver.ErrClosed)
+	}
+
+	now := time.Now()
+
+	if _, ok := s.data[key]; !ok || s.expired(key, now) {
+		return 0, wrapError("ttl", key, driver.ErrNotFound)
+	}
+
+	expires, ok := s.expires[key]
+
+	if !ok {
+		return driver.NoExpiration, nil
+	}
+
+	return expires.Sub(now), nil
+}
+
+// Expire sets the ttl for a existing key.
+func (s *Driver) Expire(key string, ttl time.Duration) error {
+	if ttl <= 0 {
+		return wrapError("expire", key, driver.ErrInvalidArgs)
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.closed {
+		return wrapError("expire", key, driver.ErrClosed)
+	}
+
+	now := time.Now()
+
+	if _, ok := s.data[key]; !ok || s.expired(key, now) {
+		return wrapError("expire", key, driver.ErrNotFound)
+	}
+
+	s.expires[key] = now.Add(ttl)
+	s.janitor.Start()
+
+	return nil
+}
+
+// Persist removes the expiration from a existing key.
+func (s *Driver) Persist(key string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.closed {
+		return wrapError("persist", key, driver.ErrClosed)
+	}
+
+	if _, ok := s.data[key]; !ok || s.expired(key, time.Now()) {
+		return wrapError("persist", key, driver.ErrNotFound)
+	}
+
+	delete(s.expires, key)
+
+	return nil
+}
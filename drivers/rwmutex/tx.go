@@ -0,0 +1,113 @@
+package rwmutex
+
+import (
+	"time"
+
+	"github.com/frozzare/go-store/driver"
+)
+
+// write is a change that is kept in a transaction until it's committed.
+type write struct {
+	data    []byte
+	deleted bool
+}
+
+// tx represents a rwmutex transaction. The write lock
+// is held for the whole transaction.
+type tx struct {
+	s      *Driver
+	now    time.Time
+	writes map[string]write
+}
+
+// get returns the data for a key with the changes in the transaction.
+func (t *tx) get(key string) ([]byte, bool) {
+	if w, ok := t.writes[key]; ok {
+		return w.data, !w.deleted
+	}
+
+	data, ok := t.s.data[key]
+
+	return data, ok && !t.s.expired(key, t.now)
+}
+
+// Exists returns true when a key exists false when not existing in store.
+func (t *tx) Exists(key string) (bool, error) {
+	_, ok := t.get(key)
+
+	return ok, nil
+}
+
+// Get returns the value for a key if any.
+func (t *tx) Get(key string, args ...interface{}) (interface{}, error) {
+	if err := driver.CheckArgs(args); err != nil {
+		return nil, wrapError("get", key, err)
+	}
+
+	data, ok := t.get(key)
+
+	if !ok {
+		return nil, wrapError("get", key, driver.ErrNotFound)
+	}
+
+	value, err := driver.Decode(t.s.codec, data, args)
+
+	if err != nil {
+		return nil, wrapError("get", key, err)
+	}
+
+	return value, nil
+}
+
+// Set key with value in store.
+func (t *tx) Set(key string, value interface{}) error {
+	data, err := t.s.codec.Marshal(value)
+
+	if err != nil {
+		return wrapError("set", key, err)
+	}
+
+	t.writes[key] = write{data: data}
+
+	return nil
+}
+
+// Delete key from store.
+func (t *tx) Delete(key string) error {
+	t.writes[key] = write{deleted: true}
+
+	return nil
+}
+
+// Update runs fn in a transaction that holds the write lock. The
+// changes is committed if fn returns nil and dropped if not.
+func (s *Driver) Update(fn func(tx driver.Tx) error) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.closed {
+		return wrapError("update", "", driver.ErrClosed)
+	}
+
+	t := &tx{s: s, now: time.Now(), writes: make(map[string]write)}
+
+	if err := fn(t); err != nil {
+		return err
+	}
+
+	for key, w := range t.writes {
+		if w.deleted {
+			delete(s.data, key)
+			delete(s.versions, key)
+			s.hub.Publish(driver.EventDelete, key)
+		} else {
+			s.data[key] = w.data
+			s.bump(key)
+			s.hub.Publish(driver.EventSet, key)
+		}
+
+		delete(s.expires, key)
+	}
+
+	return nil
+}
@@ -0,0 +1,28 @@
+package rwmutex
+
+import (
+	"net/url"
+
+	"github.com/frozzare/go-store/driver"
+)
+
+// init registers the memory:// url scheme.
+func init() {
+	driver.RegisterURL("memory", openURL)
+}
+
+// openURL opens a store from a memory:// url. The only
+// query parameter is codec.
+func openURL(u *url.URL) (driver.Driver, error) {
+	if err := driver.URLParams(u, "codec"); err != nil {
+		return nil, err
+	}
+
+	codec, err := driver.URLCodec(u)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return Open(codec)
+}
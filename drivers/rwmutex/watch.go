@@ -0,0 +1,21 @@
+package rwmutex
+
+import (
+	"context"
+
+	"github.com/frozzare/go-store/driver"
+)
+
+// Watch returns a channel that receives events for keys with the
+// prefix until the context is done. Expire events is sent when the
+// janitor removes the expired keys.
+func (s *Driver) Watch(ctx context.Context, prefix string) (<-chan driver.Event, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if s.closed {
+		return nil, wrapError("watch", prefix, driver.ErrClosed)
+	}
+
+	return s.hub.Watch(ctx, prefix), nil
+}
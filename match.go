@@ -0,0 +1,44 @@
+package store
+
+import "github.com/frozzare/go-store/driver"
+
+// Keys returns the keys in the store that matches the glob pattern,
+// see driver.Match for the syntax. The driver does the matching if
+// it implements driver.MatchDriver, otherwise all keys is filtered.
+func Keys(d driver.Driver, pattern string) ([]string, error) {
+	if m, ok := d.(driver.MatchDriver); ok {
+		return m.KeysMatch(pattern)
+	}
+
+	keys, err := d.Keys()
+
+	if err != nil {
+		return nil, err
+	}
+
+	var matched []string
+
+	for _, key := range keys {
+		if driver.Match(pattern, key) {
+			matched = append(matched, key)
+		}
+	}
+
+	return matched, nil
+}
+
+// Count returns the number of keys in the store that matches the
+// glob pattern, see driver.Match for the syntax.
+func Count(d driver.Driver, pattern string) (int64, error) {
+	if m, ok := d.(driver.MatchDriver); ok {
+		return m.CountMatch(pattern)
+	}
+
+	keys, err := Keys(d, pattern)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return int64(len(keys)), nil
+}
@@ -0,0 +1,31 @@
+package store
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/frozzare/go-assert"
+	"github.com/frozzare/go-store/driver"
+	"github.com/frozzare/go-store/drivers/rwmutex"
+)
+
+func TestKeysCount(t *testing.T) {
+	d, _ := rwmutex.Open()
+
+	d.Set("session:1", "Fredrik")
+	d.Set("session:2", "Fredrik")
+	d.Set("user:1:profile", "Fredrik")
+
+	for _, s := range []driver.Driver{d, plain{d}} {
+		keys, err := Keys(s, "session:*")
+		assert.Nil(t, err)
+
+		sort.Strings(keys)
+		assert.Equal(t, "session:1,session:2", strings.Join(keys, ","))
+
+		count, err := Count(s, "user:?:profile")
+		assert.Nil(t, err)
+		assert.Equal(t, int64(1), count)
+	}
+}
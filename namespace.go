@@ -0,0 +1,513 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/frozzare/go-store/driver"
+)
+
+// NamespaceSeparator separates the namespace from the key
+// when a namespace is emulated with key prefixes.
+const NamespaceSeparator = ":"
+
+// scanLimit is how many keys is read per Scan
+// call when a namespace lists its keys.
+const scanLimit = 1000
+
+// Namespace returns a driver that is scoped to the namespace name, so
+// Keys, Count and Flush only sees the keys in the namespace. Drivers
+// that implements driver.NamespaceDriver provides the namespace,
+// otherwise the keys is prefixed with the name and NamespaceSeparator
+// by the driver if it implements driver.PrefixDriver or by the
+// namespace itself.
+//
+// A namespace shares the connection of d, so closing a emulated
+// namespace does nothing and d must be closed when it's not used.
+func Namespace(d driver.Driver, name string) (driver.Driver, error) {
+	if name == "" {
+		return nil, fmt.Errorf("store: namespace name is empty: %w", ErrInvalidArgs)
+	}
+
+	if n, ok := d.(driver.NamespaceDriver); ok {
+		return n.Namespace(name)
+	}
+
+	return prefixed(d, name, name+NamespaceSeparator)
+}
+
+// prefixed returns a driver that prefixes the keys of d with prefix,
+// using the driver.PrefixDriver implementation of d if any.
+func prefixed(d driver.Driver, name, prefix string) (driver.Driver, error) {
+	if p, ok := d.(driver.PrefixDriver); ok {
+		return p.Prefix(prefix)
+	}
+
+	return &namespace{driver: d, name: name, prefix: prefix}, nil
+}
+
+// namespace is a driver that emulates a namespace by
+// prefixing the keys of the underlying driver.
+type namespace struct {
+	driver driver.Driver
+	name   string
+	prefix string
+}
+
+// key returns the key in the underlying driver.
+func (n *namespace) key(key string) string {
+	return n.prefix + key
+}
+
+// keys returns the keys in the underlying driver.
+func (n *namespace) keys(keys []string) []string {
+	res := make([]string, len(keys))
+
+	for i, key := range keys {
+		res[i] = n.key(key)
+	}
+
+	return res
+}
+
+// trim returns the key in the namespace.
+func (n *namespace) trim(key string) string {
+	return strings.TrimPrefix(key, n.prefix)
+}
+
+// all returns the keys in the underlying driver that is in the
+// namespace. The driver is scanned if it implements driver.ScanDriver.
+func (n *namespace) all() ([]string, error) {
+	s, ok := n.driver.(driver.ScanDriver)
+
+	if !ok {
+		keys, err := n.driver.Keys()
+
+		if err != nil {
+			return nil, err
+		}
+
+		var res []string
+
+		for _, key := range keys {
+			if strings.HasPrefix(key, n.prefix) {
+				res = append(res, key)
+			}
+		}
+
+		return res, nil
+	}
+
+	var res []string
+	var cursor string
+
+	for {
+		keys, next, err := s.Scan(n.prefix, cursor, scanLimit)
+
+		if err != nil {
+			return nil, err
+		}
+
+		res = append(res, keys...)
+
+		if next == "" {
+			return res, nil
+		}
+
+		cursor = next
+	}
+}
+
+// Count returns numbers of keys in the namespace.
+func (n *namespace) Count() (int64, error) {
+	keys, err := n.all()
+
+	if err != nil {
+		return 0, err
+	}
+
+	return int64(len(keys)), nil
+}
+
+// Delete key from the namespace.
+func (n *namespace) Delete(key string) error {
+	return n.driver.Delete(n.key(key))
+}
+
+// Exists returns true when a key exists in the namespace.
+func (n *namespace) Exists(key string) (bool, error) {
+	return n.driver.Exists(n.key(key))
+}
+
+// Get returns the value for a key in the namespace if any.
+func (n *namespace) Get(key string, args ...interface{}) (interface{}, error) {
+	return n.driver.Get(n.key(key), args...)
+}
+
+// Keys returns the keys in the namespace.
+func (n *namespace) Keys() ([]string, error) {
+	keys, err := n.all()
+
+	if err != nil {
+		return nil, err
+	}
+
+	for i, key := range keys {
+		keys[i] = n.trim(key)
+	}
+
+	return keys, nil
+}
+
+// Open opens a new store with the underlying driver
+// and returns the same namespace in it.
+func (n *namespace) Open(args ...interface{}) (driver.Driver, error) {
+	d, err := n.driver.Open(args...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &namespace{driver: d, name: n.name, prefix: n.prefix}, nil
+}
+
+// Set key with value in the namespace.
+func (n *namespace) Set(key string, value interface{}) error {
+	return n.driver.Set(n.key(key), value)
+}
+
+// Close does nothing since the underlying driver is shared.
+func (n *namespace) Close() error {
+	return nil
+}
+
+// Capabilities returns the optional features of the underlying
+// driver that the namespace supports.
+func (n *namespace) Capabilities() driver.Capabilities {
+	return driver.CapabilitiesOf(n.driver) & (driver.CapTTL | driver.CapBatch | driver.CapTx |
+		driver.CapScan | driver.CapRange | driver.CapCAS | driver.CapCounter |
+		driver.CapConditional | driver.CapWatch)
+}
+
+// Flush removes all keys in the namespace.
+func (n *namespace) Flush() error {
+	keys, err := n.all()
+
+	if err != nil {
+		return err
+	}
+
+	if len(keys) == 0 {
+		return nil
+	}
+
+	return DeleteMulti(n.driver, keys...)
+}
+
+// ttl returns the underlying driver as a driver.TTLDriver.
+func (n *namespace) ttl() (driver.TTLDriver, error) {
+	t, ok := n.driver.(driver.TTLDriver)
+
+	if !ok {
+		return nil, unsupported("ttl")
+	}
+
+	return t, nil
+}
+
+// SetWithTTL key with value in the namespace that expires after the ttl.
+func (n *namespace) SetWithTTL(key string, value interface{}, ttl time.Duration) error {
+	t, err := n.ttl()
+
+	if err != nil {
+		return err
+	}
+
+	return t.SetWithTTL(n.key(key), value, ttl)
+}
+
+// TTL returns the time left before the key expires.
+func (n *namespace) TTL(key string) (time.Duration, error) {
+	t, err := n.ttl()
+
+	if err != nil {
+		return 0, err
+	}
+
+	return t.TTL(n.key(key))
+}
+
+// Expire sets the ttl for a existing key.
+func (n *namespace) Expire(key string, ttl time.Duration) error {
+	t, err := n.ttl()
+
+	if err != nil {
+		return err
+	}
+
+	return t.Expire(n.key(key), ttl)
+}
+
+// Persist removes the expiration from a existing key.
+func (n *namespace) Persist(key string) error {
+	t, err := n.ttl()
+
+	if err != nil {
+		return err
+	}
+
+	return t.Persist(n.key(key))
+}
+
+// GetMulti returns the values for the keys that exists in the namespace.
+func (n *namespace) GetMulti(keys []string, args ...interface{}) (map[string]interface{}, error) {
+	if len(args) == 0 {
+		values, err := GetMulti(n.driver, n.keys(keys))
+
+		if err != nil {
+			return nil, err
+		}
+
+		res := make(map[string]interface{}, len(values))
+
+		for key, value := range values {
+			res[n.trim(key)] = value
+		}
+
+		return res, nil
+	}
+
+	m := reflect.ValueOf(args[0])
+
+	if m.Kind() != reflect.Ptr || m.IsNil() || m.Elem().Kind() != reflect.Map || m.Elem().Type().Key().Kind() != reflect.String {
+		return nil, ErrInvalidArgs
+	}
+
+	// The values is decoded into a map of the same type
+	// and copied without the prefix.
+	tmp := reflect.New(m.Elem().Type())
+
+	if _, err := GetMulti(n.driver, n.keys(keys), tmp.Interface()); err != nil {
+		return nil, err
+	}
+
+	m = m.Elem()
+
+	if m.IsNil() {
+		m.Set(reflect.MakeMapWithSize(m.Type(), len(keys)))
+	}
+
+	iter := tmp.Elem().MapRange()
+
+	for iter.Next() {
+		key := reflect.ValueOf(n.trim(iter.Key().String())).Convert(m.Type().Key())
+		m.SetMapIndex(key, iter.Value())
+	}
+
+	return nil, nil
+}
+
+// SetMulti keys with values in the namespace.
+func (n *namespace) SetMulti(values map[string]interface{}) error {
+	res := make(map[string]interface{}, len(values))
+
+	for key, value := range values {
+		res[n.key(key)] = value
+	}
+
+	return SetMulti(n.driver, res)
+}
+
+// DeleteMulti keys from the namespace.
+func (n *namespace) DeleteMulti(keys ...string) error {
+	return DeleteMulti(n.driver, n.keys(keys)...)
+}
+
+// tx is a transaction in a namespace.
+type tx struct {
+	n  *namespace
+	tx driver.Tx
+}
+
+// Exists returns true when a key exists in the namespace.
+func (t *tx) Exists(key string) (bool, error) {
+	return t.tx.Exists(t.n.key(key))
+}
+
+// Get returns the value for a key in the namespace if any.
+func (t *tx) Get(key string, args ...interface{}) (interface{}, error) {
+	return t.tx.Get(t.n.key(key), args...)
+}
+
+// Set key with value in the namespace.
+func (t *tx) Set(key string, value interface{}) error {
+	return t.tx.Set(t.n.key(key), value)
+}
+
+// Delete key from the namespace.
+func (t *tx) Delete(key string) error {
+	return t.tx.Delete(t.n.key(key))
+}
+
+// Update runs fn in a transaction of the underlying driver.
+func (n *namespace) Update(fn func(tx driver.Tx) error) error {
+	t, ok := n.driver.(driver.TxDriver)
+
+	if !ok {
+		return unsupported("transactions")
+	}
+
+	return t.Update(func(dtx driver.Tx) error {
+		return fn(&tx{n: n, tx: dtx})
+	})
+}
+
+// Scan returns up to limit keys with the prefix in the namespace
+// starting at the cursor. The cursor is the one of the underlying
+// driver and is passed as is.
+func (n *namespace) Scan(prefix, cursor string, limit int) ([]string, string, error) {
+	s, ok := n.driver.(driver.ScanDriver)
+
+	if !ok {
+		return nil, "", unsupported("scan")
+	}
+
+	keys, next, err := s.Scan(n.key(prefix), cursor, limit)
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	for i, key := range keys {
+		keys[i] = n.trim(key)
+	}
+
+	return keys, next, nil
+}
+
+// prefixEnd returns the first key that is greater than all keys with
+// the prefix, which is the prefix with the last byte below 0xff
+// incremented and the bytes after it removed. Keys is compared byte
+// by byte and can be any bytes, so a prefix of only 0xff bytes has no
+// end and "" is returned.
+func prefixEnd(prefix string) string {
+	for i := len(prefix) - 1; i >= 0; i-- {
+		if prefix[i] < 0xff {
+			return prefix[:i] + string([]byte{prefix[i] + 1})
+		}
+	}
+
+	return ""
+}
+
+// Range returns up to limit keys and values in the
+// namespace where start <= key < end.
+func (n *namespace) Range(start, end string, limit int, reverse bool) ([]driver.KV, error) {
+	r, ok := n.driver.(driver.RangeDriver)
+
+	if !ok {
+		return nil, unsupported("range")
+	}
+
+	upper := prefixEnd(n.prefix)
+
+	if end != "" {
+		upper = n.key(end)
+	}
+
+	pairs, err := r.Range(n.key(start), upper, limit, reverse)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range pairs {
+		pairs[i].Key = n.trim(pairs[i].Key)
+	}
+
+	return pairs, nil
+}
+
+// cas returns the underlying driver as a driver.CASDriver.
+func (n *namespace) cas() (driver.CASDriver, error) {
+	c, ok := n.driver.(driver.CASDriver)
+
+	if !ok {
+		return nil, unsupported("compare-and-set")
+	}
+
+	return c, nil
+}
+
+// GetWithVersion returns the value and version for a key.
+func (n *namespace) GetWithVersion(key string, args ...interface{}) (interface{}, string, error) {
+	c, err := n.cas()
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	return c.GetWithVersion(n.key(key), args...)
+}
+
+// CompareAndSet sets key to value if the version of the key is the given version.
+func (n *namespace) CompareAndSet(key, version string, value interface{}) error {
+	c, err := n.cas()
+
+	if err != nil {
+		return err
+	}
+
+	return c.CompareAndSet(n.key(key), version, value)
+}
+
+// IncrBy adds delta to the integer value of key and returns the new value.
+func (n *namespace) IncrBy(key string, delta int64) (int64, error) {
+	return IncrBy(n.driver, n.key(key), delta)
+}
+
+// IncrByFloat adds delta to the float value of key and returns the new value.
+func (n *namespace) IncrByFloat(key string, delta float64) (float64, error) {
+	return IncrByFloat(n.driver, n.key(key), delta)
+}
+
+// SetIfAbsent sets key to value if the key does not exist.
+func (n *namespace) SetIfAbsent(key string, value interface{}) (bool, error) {
+	return SetIfAbsent(n.driver, n.key(key), value)
+}
+
+// SetIfPresent sets key to value if the key exist.
+func (n *namespace) SetIfPresent(key string, value interface{}) (bool, error) {
+	return SetIfPresent(n.driver, n.key(key), value)
+}
+
+// Watch returns a channel that receives events for keys with the
+// prefix in the namespace, Flush events of the underlying driver is
+// also sent since they removes the keys in the namespace.
+func (n *namespace) Watch(ctx context.Context, prefix string) (<-chan driver.Event, error) {
+	events, err := Watch(ctx, n.driver, n.key(prefix))
+
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan driver.Event, cap(events))
+
+	go func() {
+		defer close(out)
+
+		for e := range events {
+			e.Key = n.trim(e.Key)
+
+			select {
+			case out <- e:
+			default:
+			}
+		}
+	}()
+
+	return out, nil
+}
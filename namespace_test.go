@@ -0,0 +1,139 @@
+package store
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/frozzare/go-assert"
+	"github.com/frozzare/go-store/driver"
+	"github.com/frozzare/go-store/drivers/rwmutex"
+	"github.com/frozzare/go-store/storetest"
+)
+
+func TestNamespace(t *testing.T) {
+	d, _ := rwmutex.Open()
+	d.Set("name", "root")
+
+	sessions, err := Namespace(d, "sessions")
+	assert.Nil(t, err)
+
+	users, _ := Namespace(d, "users")
+
+	sessions.Set("a", "1")
+	sessions.Set("b", "2")
+	users.Set("a", "Fredrik")
+
+	v, _ := sessions.Get("a")
+	assert.Equal(t, "1", v.(string))
+
+	v, _ = users.Get("a")
+	assert.Equal(t, "Fredrik", v.(string))
+
+	keys, _ := sessions.Keys()
+	sort.Strings(keys)
+	assert.Equal(t, []string{"a", "b"}, keys)
+
+	count, _ := sessions.Count()
+	assert.Equal(t, int64(2), count)
+
+	assert.Nil(t, sessions.Flush())
+
+	count, _ = sessions.Count()
+	assert.Equal(t, int64(0), count)
+
+	count, _ = d.Count()
+	assert.Equal(t, int64(2), count)
+
+	_, err = Namespace(d, "")
+	assert.NotNil(t, err)
+}
+
+func TestNamespaceOptional(t *testing.T) {
+	d, _ := rwmutex.Open()
+	n, _ := Namespace(d, "users")
+
+	assert.Nil(t, SetMulti(n, map[string]interface{}{"a": 1, "b": 2, "c": 3}))
+
+	var values map[string]int
+	_, err := GetMulti(n, []string{"a", "b", "x"}, &values)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]int{"a": 1, "b": 2}, values)
+
+	pairs, err := n.(driver.RangeDriver).Range("b", "", 0, false)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(pairs))
+	assert.Equal(t, "b", pairs[0].Key)
+	assert.Equal(t, "c", pairs[1].Key)
+
+	keys, _ := Keys(n, "[ab]")
+	sort.Strings(keys)
+	assert.Equal(t, []string{"a", "b"}, keys)
+
+	nested, _ := Namespace(n, "admins")
+	nested.Set("a", 4)
+
+	v, _ := d.Get("users:admins:a")
+	assert.Equal(t, float64(4), v.(float64))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events, _ := Watch(ctx, n, "")
+	n.Delete("a")
+	assert.Equal(t, driver.Event{Type: driver.EventDelete, Key: "a"}, <-events)
+
+	_, err = Namespace(plain{d}, "users")
+	assert.Nil(t, err)
+}
+
+func TestNamespaceConformance(t *testing.T) {
+	d, _ := rwmutex.Open()
+
+	storetest.Run(t, func() driver.Driver {
+		n, _ := Namespace(d, "conformance")
+		return n
+	}, storetest.KeepOpen())
+}
+
+// prefixer is a driver that records the prefix it's asked for.
+type prefixer struct {
+	driver.Driver
+	prefix string
+}
+
+func (p *prefixer) Prefix(prefix string) (driver.Driver, error) {
+	p.prefix = prefix
+	return p, nil
+}
+
+func TestNamespacePrefixDriver(t *testing.T) {
+	d, _ := rwmutex.Open()
+	p := &prefixer{Driver: d}
+
+	n, err := Namespace(p, "users")
+	assert.Nil(t, err)
+	assert.Equal(t, "users"+NamespaceSeparator, p.prefix)
+
+	_, ok := n.(*prefixer)
+	assert.True(t, ok)
+}
+
+func TestNamespaceRangeEnd(t *testing.T) {
+	d, _ := rwmutex.Open()
+	n, _ := Namespace(d, "users")
+
+	// Keys is any bytes, not only valid UTF-8.
+	n.Set("\xf5", 1)
+	n.Set("\xff\xff", 2)
+	d.Set("users;", 3)
+
+	pairs, err := n.(driver.RangeDriver).Range("", "", 0, false)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(pairs))
+	assert.Equal(t, "\xff\xff", pairs[1].Key)
+
+	assert.Equal(t, "users;", prefixEnd("users:"))
+	assert.Equal(t, "b", prefixEnd("a\xff"))
+	assert.Equal(t, "", prefixEnd("\xff\xff"))
+}
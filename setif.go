@@ -0,0 +1,27 @@
+package store
+
+import "github.com/frozzare/go-store/driver"
+
+// SetIfAbsent sets key to value if the key does not exist
+// and reports if the value was set.
+func SetIfAbsent(d driver.Driver, key string, value interface{}) (bool, error) {
+	c, ok := d.(driver.ConditionalDriver)
+
+	if !ok {
+		return false, unsupported("conditional writes")
+	}
+
+	return c.SetIfAbsent(key, value)
+}
+
+// SetIfPresent sets key to value if the key exist
+// and reports if the value was set.
+func SetIfPresent(d driver.Driver, key string, value interface{}) (bool, error) {
+	c, ok := d.(driver.ConditionalDriver)
+
+	if !ok {
+		return false, unsupported("conditional writes")
+	}
+
+	return c.SetIfPresent(key, value)
+}
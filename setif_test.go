@@ -0,0 +1,25 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/frozzare/go-assert"
+	"github.com/frozzare/go-store/drivers/rwmutex"
+)
+
+func TestSetIfAbsent(t *testing.T) {
+	d, _ := rwmutex.Open()
+
+	ok, err := SetIfAbsent(d, "job", 1)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+
+	ok, _ = SetIfAbsent(d, "job", 2)
+	assert.False(t, ok)
+
+	ok, _ = SetIfPresent(d, "job", 3)
+	assert.True(t, ok)
+
+	_, err = SetIfAbsent(plain{d}, "job", 4)
+	assert.NotNil(t, err)
+}
@@ -2,39 +2,40 @@ package store
 
 import (
 	"fmt"
-	"sync"
 
 	"github.com/frozzare/go-store/driver"
-	"github.com/frozzare/go-store/drivers/rwmutex"
-)
 
-var (
-	driversMu sync.RWMutex
-	drivers   = make(map[string]driver.Driver)
+	// The rwmutex driver is the default driver.
+	_ "github.com/frozzare/go-store/drivers/rwmutex"
 )
 
-// init register the default store drivers.
-func init() {
-	Register("rwmutex", &rwmutex.Driver{})
-}
+// Factory opens a driver with the Open args.
+type Factory = driver.Factory
 
 // Register makes a store driver available by the provided name.
 // If Register is called twice with the same name or if driver is nil,
-// it panics.
-func Register(name string, driver driver.Driver) {
-	driversMu.Lock()
-
-	defer driversMu.Unlock()
+// it panics. The bundled drivers registers themselves when imported.
+func Register(name string, d driver.Driver) {
+	driver.Register(name, d)
+}
 
-	if driver == nil {
-		panic("store: Register driver is nil")
-	}
+// RegisterFactory makes a store driver available by the provided name
+// that is opened by open with the defaults followed by the Open args.
+// If RegisterFactory is called twice with the same name or if open is
+// nil, it panics.
+func RegisterFactory(name string, open Factory, defaults ...interface{}) {
+	driver.RegisterFactory(name, open, defaults...)
+}
 
-	if _, dup := drivers[name]; dup {
-		panic("store: Register called twice for driver " + name)
-	}
+// Unregister removes the store driver registered by the
+// provided name, it's mostly useful in tests.
+func Unregister(name string) {
+	driver.Unregister(name)
+}
 
-	drivers[name] = driver
+// Drivers returns a sorted list of the names of the registered drivers.
+func Drivers() []string {
+	return driver.Drivers()
 }
 
 // Open opens a store driver and return it's implementation
@@ -42,17 +43,45 @@ func Register(name string, driver driver.Driver) {
 func Open(args ...interface{}) (driver.Driver, error) {
 	name := "rwmutex"
 	if len(args) > 0 {
-		name = args[0].(string)
+		n, ok := args[0].(string)
+
+		if !ok {
+			return nil, fmt.Errorf("store: driver name must be a string, got %T: %w", args[0], ErrInvalidArgs)
+		}
+
+		name = n
 		args = args[1:]
 	}
 
-	driversMu.RLock()
-	driver, ok := drivers[name]
-	driversMu.RUnlock()
+	open, ok := driver.Lookup(name)
 
 	if !ok {
 		return nil, fmt.Errorf("store: unknown driver %q (forgotten import?)", name)
 	}
 
-	return driver.Open(args...)
+	return open(args...)
 }
+
+// Error is the error type returned by the store drivers. It records
+// the operation, key and driver name of the failed call.
+type Error = driver.Error
+
+var (
+	// ErrNotFound is returned when a key does not exist in the store.
+	ErrNotFound = driver.ErrNotFound
+
+	// ErrClosed is returned when a store is used after it has been closed.
+	ErrClosed = driver.ErrClosed
+
+	// ErrInvalidArgs is returned when a driver is called with arguments
+	// it can not handle.
+	ErrInvalidArgs = driver.ErrInvalidArgs
+
+	// ErrConflict is returned when a transaction could not be
+	// committed since a key it read was changed by someone else.
+	ErrConflict = driver.ErrConflict
+
+	// ErrNotSupported is returned when a optional feature
+	// is used that the driver does not support.
+	ErrNotSupported = driver.ErrNotSupported
+)
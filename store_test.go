@@ -1,9 +1,11 @@
 package store
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/frozzare/go-assert"
+	"github.com/frozzare/go-store/codec"
 	"github.com/frozzare/go-store/drivers/redis"
 	"github.com/frozzare/go-store/drivers/rwmutex"
 )
@@ -25,6 +27,16 @@ func TestOpenDriver(t *testing.T) {
 	assert.Nil(t, err)
 }
 
+func TestOpenInvalidArgs(t *testing.T) {
+	driver, err := Open(1)
+	assert.Nil(t, driver)
+	assert.True(t, errors.Is(err, ErrInvalidArgs))
+
+	driver, err = Open("rwmutex", "/tmp/store")
+	assert.Nil(t, driver)
+	assert.True(t, errors.Is(err, ErrInvalidArgs))
+}
+
 func TestRegisterNilDriver(t *testing.T) {
 	defer func() {
 		if r := recover(); r == nil {
@@ -44,8 +56,35 @@ func TestRegisterSameDriver(t *testing.T) {
 }
 
 func TestRegisterNewDriver(t *testing.T) {
+	Unregister("redis")
 	Register("redis", &redis.Driver{})
 	driver, err := Open("redis")
 	assert.NotNil(t, driver)
 	assert.Nil(t, err)
 }
+
+func TestDrivers(t *testing.T) {
+	// The imported drivers registers themselves.
+	assert.Equal(t, []string{"redis", "rwmutex"}, Drivers())
+
+	driver, err := Open("redis")
+	assert.NotNil(t, driver)
+	assert.Nil(t, err)
+}
+
+func TestRegisterFactory(t *testing.T) {
+	RegisterFactory("memory-gob", rwmutex.Open, codec.Gob{})
+	defer Unregister("memory-gob")
+
+	d, err := Open("memory-gob")
+	assert.Nil(t, err)
+
+	d.Set("name", "Fredrik")
+
+	var v string
+	d.Get("name", &v)
+	assert.Equal(t, "Fredrik", v)
+
+	_, err = d.(*rwmutex.Driver).Get("name")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,461 @@
+// Package storetest provides a conformance test suite for store drivers.
+//
+// A driver package runs it from its tests, with Options for the
+// behavior that differs between drivers:
+//
+//	func TestConformance(t *testing.T) {
+//		storetest.Run(t, func() driver.Driver {
+//			d, _ := Open()
+//			return d
+//		})
+//	}
+package storetest
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/frozzare/go-store/driver"
+)
+
+// Concurrency is how many goroutines the concurrent
+// access test sets and gets keys from.
+var Concurrency = 8
+
+// options is the behavior of the driver that the tests depends on.
+type options struct {
+	memory   bool
+	keepOpen bool
+}
+
+// Option tells the tests about behavior that differs between drivers.
+type Option func(*options)
+
+// Memory tells the tests that the store is empty when it's opened
+// again after Close, so the values are not checked after reopening.
+func Memory() Option {
+	return func(o *options) {
+		o.memory = true
+	}
+}
+
+// KeepOpen tells the tests that Close does not close the store, e.g.
+// for a namespace that shares the store, so the calls after Close is
+// not checked for ErrClosed.
+func KeepOpen() Option {
+	return func(o *options) {
+		o.keepOpen = true
+	}
+}
+
+// Run runs the conformance tests for the driver returned by open. The
+// store is flushed before each test and closed after it, so open is
+// called once for each test and must return a driver for the same
+// store each time.
+func Run(t *testing.T, open func() driver.Driver, opts ...Option) {
+	var o options
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, d driver.Driver)
+	}{
+		{"NotFound", testNotFound},
+		{"SetGet", testSetGet},
+		{"SetGetStruct", testSetGetStruct},
+		{"Exists", testExists},
+		{"Count", testCount},
+		{"Keys", testKeys},
+		{"Flush", testFlush},
+		{"Concurrent", testConcurrent},
+	}
+
+	for _, test := range tests {
+		fn := test.fn
+
+		t.Run(test.name, func(t *testing.T) {
+			d := open()
+
+			if d == nil {
+				t.Fatal("open returned a nil driver")
+			}
+
+			if err := d.Flush(); err != nil {
+				t.Fatalf("Flush: %v", err)
+			}
+
+			defer d.Close()
+
+			fn(t, d)
+		})
+	}
+
+	t.Run("CloseReopen", func(t *testing.T) {
+		testCloseReopen(t, open, o)
+	})
+
+	t.Run("CloseConcurrent", func(t *testing.T) {
+		testCloseConcurrent(t, open)
+	})
+}
+
+// testNotFound checks the missing key semantics.
+func testNotFound(t *testing.T, d driver.Driver) {
+	v, err := d.Get("missing")
+
+	if v != nil {
+		t.Errorf("Get of missing key returned %v, want nil", v)
+	}
+
+	if !errors.Is(err, driver.ErrNotFound) {
+		t.Errorf("Get of missing key returned error %v, want ErrNotFound", err)
+	}
+
+	var s string
+
+	if _, err := d.Get("missing", &s); !errors.Is(err, driver.ErrNotFound) {
+		t.Errorf("Get of missing key into a pointer returned error %v, want ErrNotFound", err)
+	}
+
+	if ok, err := d.Exists("missing"); ok || err != nil {
+		t.Errorf("Exists of missing key returned %v, %v, want false, nil", ok, err)
+	}
+
+	if err := d.Delete("missing"); err != nil {
+		t.Errorf("Delete of missing key returned error %v, want nil", err)
+	}
+}
+
+// testSetGet checks that values can be set, read and overwritten.
+func testSetGet(t *testing.T, d driver.Driver) {
+	mustSet(t, d, "name", "Fredrik")
+
+	if v, err := d.Get("name"); err != nil || v != "Fredrik" {
+		t.Errorf("Get returned %v, %v, want Fredrik, nil", v, err)
+	}
+
+	mustSet(t, d, "name", "Elli")
+
+	if v, err := d.Get("name"); err != nil || v != "Elli" {
+		t.Errorf("Get after overwrite returned %v, %v, want Elli, nil", v, err)
+	}
+
+	mustSet(t, d, "map", map[string]interface{}{"hello": "world"})
+
+	v, err := d.Get("map")
+
+	if m, ok := v.(map[string]interface{}); err != nil || !ok || m["hello"] != "world" {
+		t.Errorf("Get of map returned %v, %v, want map[hello:world], nil", v, err)
+	}
+}
+
+// person is the struct that is set and decoded.
+type person struct {
+	Name string
+	Age  int
+}
+
+// testSetGetStruct checks that values can be decoded into a pointer.
+func testSetGetStruct(t *testing.T, d driver.Driver) {
+	want := person{Name: "Fredrik", Age: 30}
+
+	mustSet(t, d, "person", &want)
+
+	var got person
+
+	if _, err := d.Get("person", &got); err != nil || got != want {
+		t.Errorf("Get into pointer returned %+v, %v, want %+v, nil", got, err, want)
+	}
+
+	var p *person
+
+	if _, err := d.Get("person", &p); err != nil || p == nil || *p != want {
+		t.Errorf("Get into pointer to pointer returned %+v, %v, want %+v, nil", p, err, want)
+	}
+}
+
+// testExists checks Exists before and after Set and Delete.
+func testExists(t *testing.T, d driver.Driver) {
+	mustSet(t, d, "name", "Fredrik")
+
+	if ok, err := d.Exists("name"); !ok || err != nil {
+		t.Errorf("Exists returned %v, %v, want true, nil", ok, err)
+	}
+
+	if err := d.Delete("name"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	if ok, err := d.Exists("name"); ok || err != nil {
+		t.Errorf("Exists after Delete returned %v, %v, want false, nil", ok, err)
+	}
+}
+
+// testCount checks Count of a empty store and after Set and Delete.
+func testCount(t *testing.T, d driver.Driver) {
+	expectCount(t, d, 0)
+
+	mustSet(t, d, "a", 1)
+	mustSet(t, d, "b", 2)
+	mustSet(t, d, "b", 3)
+	expectCount(t, d, 2)
+
+	if err := d.Delete("a"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	expectCount(t, d, 1)
+
+	if err := d.Delete("a"); err != nil {
+		t.Fatalf("Delete of deleted key: %v", err)
+	}
+
+	expectCount(t, d, 1)
+}
+
+// testKeys checks Keys of a empty store, after Set and after Flush.
+func testKeys(t *testing.T, d driver.Driver) {
+	expectKeys(t, d)
+
+	mustSet(t, d, "b", 1)
+	mustSet(t, d, "a", 2)
+	expectKeys(t, d, "a", "b")
+
+	if err := d.Delete("b"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	expectKeys(t, d, "a")
+
+	if err := d.Flush(); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+
+	expectKeys(t, d)
+}
+
+// testFlush checks that Flush removes all keys and
+// that a empty store can be flushed.
+func testFlush(t *testing.T, d driver.Driver) {
+	if err := d.Flush(); err != nil {
+		t.Errorf("Flush of empty store returned error %v, want nil", err)
+	}
+
+	mustSet(t, d, "a", 1)
+	mustSet(t, d, "b", 2)
+
+	if err := d.Flush(); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+
+	expectCount(t, d, 0)
+
+	if _, err := d.Get("a"); !errors.Is(err, driver.ErrNotFound) {
+		t.Errorf("Get after Flush returned error %v, want ErrNotFound", err)
+	}
+}
+
+// testConcurrent checks that the driver can be used from
+// many goroutines at the same time.
+func testConcurrent(t *testing.T, d driver.Driver) {
+	const keys = 10
+
+	var wg sync.WaitGroup
+	errs := make(chan error, Concurrency*keys)
+
+	for i := 0; i < Concurrency; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			for j := 0; j < keys; j++ {
+				key := fmt.Sprintf("key-%d-%d", i, j)
+
+				if err := d.Set(key, j); err != nil {
+					errs <- fmt.Errorf("Set %s: %v", key, err)
+					continue
+				}
+
+				var v int
+
+				if _, err := d.Get(key, &v); err != nil || v != j {
+					errs <- fmt.Errorf("Get %s returned %v, %v, want %d, nil", key, v, err, j)
+				}
+			}
+		}(i)
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Error(err)
+	}
+
+	expectCount(t, d, int64(Concurrency*keys))
+}
+
+// testCloseReopen checks that the calls after Close returns ErrClosed
+// and that a store can be opened again with the values kept.
+func testCloseReopen(t *testing.T, open func() driver.Driver, o options) {
+	d := open()
+
+	if err := d.Flush(); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+
+	mustSet(t, d, "name", "Fredrik")
+
+	if err := d.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	if !o.keepOpen {
+		if _, err := d.Get("name"); !errors.Is(err, driver.ErrClosed) {
+			t.Errorf("Get after Close returned error %v, want ErrClosed", err)
+		}
+
+		if err := d.Set("name", "Elli"); !errors.Is(err, driver.ErrClosed) {
+			t.Errorf("Set after Close returned error %v, want ErrClosed", err)
+		}
+
+		if err := d.Close(); err != nil {
+			t.Errorf("Close of closed store returned error %v, want nil", err)
+		}
+	}
+
+	d = open()
+	defer d.Close()
+
+	if !o.memory {
+		var name string
+
+		if _, err := d.Get("name", &name); err != nil || name != "Fredrik" {
+			t.Errorf("Get after reopen returned %v, %v, want Fredrik, nil", name, err)
+		}
+	}
+
+	mustSet(t, d, "age", 30)
+
+	var age int
+
+	if _, err := d.Get("age", &age); err != nil || age != 30 {
+		t.Errorf("Get after reopen returned %v, %v, want 30, nil", age, err)
+	}
+
+	if err := d.Flush(); err != nil {
+		t.Errorf("Flush after reopen: %v", err)
+	}
+}
+
+// mustSet sets key to value and stops the test if it fails.
+func mustSet(t *testing.T, d driver.Driver, key string, value interface{}) {
+	t.Helper()
+
+	if err := d.Set(key, value); err != nil {
+		t.Fatalf("Set %s: %v", key, err)
+	}
+}
+
+// expectCount checks that Count returns want.
+func expectCount(t *testing.T, d driver.Driver, want int64) {
+	t.Helper()
+
+	if count, err := d.Count(); err != nil || count != want {
+		t.Errorf("Count returned %d, %v, want %d, nil", count, err, want)
+	}
+}
+
+// expectKeys checks that Keys returns the wanted keys in any order.
+func expectKeys(t *testing.T, d driver.Driver, want ...string) {
+	t.Helper()
+
+	keys, err := d.Keys()
+
+	if err != nil {
+		t.Errorf("Keys: %v", err)
+		return
+	}
+
+	sort.Strings(keys)
+
+	if len(keys) != len(want) || (len(want) > 0 && !reflect.DeepEqual(keys, want)) {
+		t.Errorf("Keys returned %v, want %v", keys, want)
+	}
+}
+
+// testCloseConcurrent checks that the store can be closed while it's
+// used, the calls then either succeeds or returns ErrClosed.
+func testCloseConcurrent(t *testing.T, open func() driver.Driver) {
+	const keys = 50
+
+	d := open()
+
+	if err := d.Flush(); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, Concurrency)
+	started := make(chan struct{}, Concurrency)
+
+	for i := 0; i < Concurrency; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			for j := 0; j < keys; j++ {
+				key := fmt.Sprintf("key-%d-%d", i, j)
+				err := d.Set(key, j)
+
+				if j == 0 {
+					started <- struct{}{}
+				}
+
+				if err == nil {
+					_, err = d.Get(key)
+				}
+
+				if errors.Is(err, driver.ErrClosed) {
+					return
+				}
+
+				if err != nil {
+					errs <- fmt.Errorf("%s: %v, want nil or ErrClosed", key, err)
+					return
+				}
+			}
+		}(i)
+	}
+
+	for i := 0; i < Concurrency; i++ {
+		<-started
+	}
+
+	if err := d.Close(); err != nil {
+		t.Errorf("Close: %v", err)
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Error(err)
+	}
+
+	d = open()
+	defer d.Close()
+
+	if err := d.Flush(); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+}
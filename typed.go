@@ -0,0 +1,108 @@
+package store
+
+import (
+	"errors"
+
+	"github.com/frozzare/go-store/driver"
+)
+
+// Typed wraps a store driver and decodes all
+// values into the given type.
+type Typed[T any] struct {
+	driver driver.Driver
+}
+
+// NewTyped creates a new typed store for the given driver.
+func NewTyped[T any](d driver.Driver) *Typed[T] {
+	return &Typed[T]{driver: d}
+}
+
+// Driver returns the underlying store driver.
+func (t *Typed[T]) Driver() driver.Driver {
+	return t.driver
+}
+
+// Get returns the value for a key and true if the
+// key exists or the zero value and false if not.
+func (t *Typed[T]) Get(key string) (T, bool, error) {
+	var value T
+
+	if _, err := t.driver.Get(key, &value); err != nil {
+		var zero T
+
+		if errors.Is(err, driver.ErrNotFound) {
+			return zero, false, nil
+		}
+
+		return zero, false, err
+	}
+
+	return value, true, nil
+}
+
+// Set key with value in store.
+func (t *Typed[T]) Set(key string, value T) error {
+	return t.driver.Set(key, value)
+}
+
+// Update sets key to the value returned by fn for the current value,
+// ok is false if the key does not exist. fn is called again if the
+// key was changed by someone else, see Update.
+func (t *Typed[T]) Update(key string, fn func(old T, ok bool) (T, error)) error {
+	return retry(t.driver, func(c driver.CASDriver) error {
+		var old T
+
+		_, version, err := c.GetWithVersion(key, &old)
+
+		if err != nil && !errors.Is(err, driver.ErrNotFound) {
+			return err
+		}
+
+		value, err := fn(old, err == nil)
+
+		if err != nil {
+			return err
+		}
+
+		return c.CompareAndSet(key, version, value)
+	})
+}
+
+// Delete key from store.
+func (t *Typed[T]) Delete(key string) error {
+	return t.driver.Delete(key)
+}
+
+// GetMany returns the values for the given keys.
+// Keys that does not exist is left out.
+func (t *Typed[T]) GetMany(keys ...string) (map[string]T, error) {
+	values := make(map[string]T, len(keys))
+
+	if _, err := GetMulti(t.driver, keys, &values); err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
+
+// SetMany sets the keys with values in store.
+func (t *Typed[T]) SetMany(values map[string]T) error {
+	m := make(map[string]interface{}, len(values))
+
+	for key, value := range values {
+		m[key] = value
+	}
+
+	return SetMulti(t.driver, m)
+}
+
+// All returns all keys and values in the store.
+func (t *Typed[T]) All() (map[string]T, error) {
+	keys, err := t.driver.Keys()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return t.GetMany(keys...)
+}
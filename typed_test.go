@@ -0,0 +1,58 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/frozzare/go-assert"
+	"github.com/frozzare/go-store/drivers/rwmutex"
+)
+
+type person struct {
+	Name string
+}
+
+func TestTypedGetSet(t *testing.T) {
+	d, _ := rwmutex.Open()
+	s := NewTyped[person](d)
+
+	p, ok, err := s.Get("name")
+	assert.False(t, ok)
+	assert.Nil(t, err)
+	assert.Equal(t, "", p.Name)
+
+	assert.Nil(t, s.Set("name", person{Name: "Fredrik"}))
+
+	p, ok, err = s.Get("name")
+	assert.True(t, ok)
+	assert.Nil(t, err)
+	assert.Equal(t, "Fredrik", p.Name)
+}
+
+func TestTypedNumber(t *testing.T) {
+	d, _ := rwmutex.Open()
+	s := NewTyped[int](d)
+
+	s.Set("number", 42)
+
+	v, ok, _ := s.Get("number")
+	assert.True(t, ok)
+	assert.Equal(t, 42, v)
+}
+
+func TestTypedGetMany(t *testing.T) {
+	d, _ := rwmutex.Open()
+	s := NewTyped[string](d)
+
+	s.Set("a", "1")
+	s.Set("b", "2")
+
+	values, err := s.GetMany("a", "b", "c")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(values))
+	assert.Equal(t, "1", values["a"])
+	assert.Equal(t, "2", values["b"])
+
+	values, err = s.All()
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(values))
+}
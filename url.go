@@ -0,0 +1,55 @@
+package store
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/frozzare/go-store/driver"
+)
+
+// OpenURL opens a store from a URL whose scheme is registered with
+// driver.RegisterURL by the driver package, so the driver package
+// must be imported. The bundled drivers registers these schemes:
+//
+//	memory://
+//	redis://:password@localhost:6379/2
+//	bolt:///var/lib/app.db?bucket=x&timeout=1s
+//	leveldb:///var/lib/app
+//	buntdb://:memory:
+//	rethinkdb://localhost:28015/database/table
+//
+// The codec query parameter selects the value codec for every scheme.
+// The prefix query parameter prefixes all keys with its value like
+// Namespace, e.g. redis://localhost/?prefix=app: stores the key
+// name as app:name. Drivers that implements driver.PrefixDriver
+// prefixes the keys themselves.
+func OpenURL(rawurl string) (driver.Driver, error) {
+	u, err := url.Parse(rawurl)
+
+	if err != nil {
+		return nil, fmt.Errorf("store: invalid url: %w", err)
+	}
+
+	open, ok := driver.LookupURL(u.Scheme)
+
+	if !ok {
+		return nil, fmt.Errorf("store: unknown url scheme %q (forgotten import?)", u.Scheme)
+	}
+
+	query := u.Query()
+	prefix := query.Get("prefix")
+	query.Del("prefix")
+	u.RawQuery = query.Encode()
+
+	d, err := open(u)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if prefix != "" {
+		return prefixed(d, prefix, prefix)
+	}
+
+	return d, nil
+}
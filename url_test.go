@@ -0,0 +1,43 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/frozzare/go-assert"
+	"github.com/frozzare/go-store/drivers/rwmutex"
+)
+
+func TestOpenURL(t *testing.T) {
+	d, err := OpenURL("memory://?codec=gob")
+	assert.Nil(t, err)
+
+	assert.Nil(t, d.Set("name", "Fredrik"))
+
+	var v string
+	_, err = d.Get("name", &v)
+	assert.Nil(t, err)
+	assert.Equal(t, "Fredrik", v)
+
+	_, err = OpenURL("unknown://")
+	assert.NotNil(t, err)
+
+	_, err = OpenURL("memory://?codec=xml")
+	assert.NotNil(t, err)
+
+	_, err = OpenURL("memory://?size=1")
+	assert.NotNil(t, err)
+}
+
+func TestOpenURLPrefix(t *testing.T) {
+	d, err := OpenURL("memory://?prefix=app:")
+	assert.Nil(t, err)
+
+	d.Set("name", "Fredrik")
+
+	n := d.(*namespace)
+	v, _ := n.driver.Get("app:name")
+	assert.Equal(t, "Fredrik", v.(string))
+
+	_, ok := n.driver.(*rwmutex.Driver)
+	assert.True(t, ok)
+}
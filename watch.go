@@ -0,0 +1,19 @@
+package store
+
+import (
+	"context"
+
+	"github.com/frozzare/go-store/driver"
+)
+
+// Watch returns a channel that receives events for keys with the
+// prefix until the context is done, then the channel is closed.
+func Watch(ctx context.Context, d driver.Driver, prefix string) (<-chan driver.Event, error) {
+	w, ok := d.(driver.WatchDriver)
+
+	if !ok {
+		return nil, unsupported("watch")
+	}
+
+	return w.Watch(ctx, prefix)
+}
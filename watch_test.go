@@ -0,0 +1,26 @@
+package store
+
+import (
+	"context"
+	"testing"
+
+	"github.com/frozzare/go-assert"
+	"github.com/frozzare/go-store/driver"
+	"github.com/frozzare/go-store/drivers/rwmutex"
+)
+
+func TestWatch(t *testing.T) {
+	d, _ := rwmutex.Open()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events, err := Watch(ctx, d, "")
+	assert.Nil(t, err)
+
+	d.Set("name", "Fredrik")
+	assert.Equal(t, driver.Event{Type: driver.EventSet, Key: "name"}, <-events)
+
+	_, err = Watch(ctx, plain{d}, "")
+	assert.NotNil(t, err)
+}